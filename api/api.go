@@ -1,16 +1,193 @@
 package api
 
 import (
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/kokoichi206/go-git-stats/api/internal/rest"
 	"github.com/kokoichi206/go-git-stats/util"
 )
 
 // struct that implements ApiCaller
 type Api struct {
 	config util.Config
+	client *http.Client
+	// Installation token of the GitHub App, nil if the app is not configured.
+	app *appToken
+	// Tokens which share the requests, nil with a single token.
+	pool *tokenPool
+	// Transport which logs requests.
+	log *logTransport
+}
+
+// Returns the ApiCaller of the GitHub REST API.
+// Requests of all methods share one client, whose connections are reused.
+func New(config util.Config, opts ...Option) ApiCaller {
+
+	o := newOptions(opts)
+	a := &Api{config: config}
+
+	// From the server: middlewares, the token pool, the cache, the user agent, the telemetry and the logger.
+	wrap := o.wrap
+	if tokens := config.TokenPool(); len(tokens) > 1 {
+		a.pool = newTokenPool(tokens)
+		wrap = func(next http.RoundTripper) http.RoundTripper {
+			return a.pool.transport(o.wrap(next))
+		}
+	}
+	a.client, a.log = o.newClient(config, wrap)
+
+	if config.App.Configured() {
+		a.app = newAppToken(config.App, config.ApiBaseURL, a.client)
+	}
+
+	return a
+}
+
+// Returns the client of the configurations with the options,
+// which the backends of other providers use to send requests with the user agent, the logger and the telemetry.
+func NewHTTPClient(config util.Config, opts ...Option) *http.Client {
+	o := newOptions(opts)
+	client, _ := o.newClient(config, o.wrap)
+	return client
+}
+
+// Returns the client whose transport to the server is wrapped, and the transport which logs requests.
+func (o options) newClient(config util.Config, wrap Middleware) (*http.Client, *logTransport) {
+
+	var client *http.Client
+	if o.client != nil {
+		// The given client is not modified.
+		c := *o.client
+		transport := c.Transport
+		if transport == nil {
+			transport = http.DefaultTransport
+		}
+		c.Transport = wrap(transport)
+		client = &c
+	} else {
+		client = config.HTTPClientWith(wrap)
+	}
+	if o.timeout > 0 {
+		client.Timeout = o.timeout
+	}
+	if o.userAgent != "" {
+		client.Transport = &userAgentTransport{userAgent: o.userAgent, next: client.Transport}
+	}
+
+	// Requests are recorded and logged above the cache to tell cache hits,
+	// and the logger counts attempts for the telemetry.
+	if o.telemetry != nil {
+		client.Transport = newTelemetryTransport(o.telemetry, client.Transport)
+	}
+	log := newLogTransport(client.Transport)
+	log.set(o.logger)
+	client.Transport = log
+
+	return client, log
+}
+
+// Set the Authorization header of the request
+// with the installation token of the GitHub App or the access token (if any).
+func (a *Api) setAuthorization(req *http.Request) error {
+
+	token := a.config.Token
+	if a.app != nil {
+		var err error
+		token, _, err = a.app.get()
+		if err != nil {
+			return err
+		}
+	}
+
+	if token != "" {
+		req.Header.Set("Authorization", fmt.Sprintf("token %s", token))
+	}
+	return nil
 }
 
-func New(config util.Config) ApiCaller {
-	return &Api{
-		config: config,
+// Call GET method of the REST API and return the body of the response.
+// The request is authorized with the token if authorized is true.
+// Server errors are retried, but user's errors (4xx) are not,
+// and statistics which GitHub is still computing (202) are requested again after statsWait.
+func (a *Api) get(URL string, authorized bool) ([]byte, error) {
+
+	retries := 3
+
+	req, err := newRequest("GET", URL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to http.NewRequest: %w", err)
+	}
+
+	// Set request header.
+	req.Header.Add("Accept", "application/vnd.github+json")
+	if authorized {
+		if err := a.setAuthorization(req); err != nil {
+			return nil, err
+		}
 	}
+
+	var resp *http.Response
+	// Error of the statistics which are still computed by GitHub.
+	var pending error
+	success := false
+	for retries > 0 {
+		// > If the returned error is nil, the Response will contain a non-nil
+		// > Body which the user is expected to close.
+		resp, err = a.client.Do(req)
+
+		if err != nil {
+			// Invalid URL (Like different scheme) etc.
+			pending = nil
+			retries -= 1
+			continue
+		}
+
+		if resp.StatusCode == http.StatusOK {
+			// Success!
+			success = true
+			break
+		}
+
+		if resp.StatusCode/100 == 4 {
+			// If the StatusCode starts with 4, it is user's error,
+			// so it should not be retried.
+			err := rest.NewStatusError(resp)
+			resp.Body.Close()
+			return nil, err
+		}
+
+		if resp.StatusCode == http.StatusAccepted {
+			// Failed to find cache and GitHub started to create statistics.
+			// Sleep some time and retry.
+			//
+			// See GitHub documentation: https://docs.github.com/en/rest/metrics/statistics#a-word-about-caching
+			pending = rest.NewStatusError(resp)
+			resp.Body.Close()
+			time.Sleep(statsWait)
+			retries -= 1
+			continue
+		}
+		resp.Body.Close()
+
+		pending = nil
+		retries -= 1
+	}
+
+	if !success {
+		if pending != nil {
+			return nil, pending
+		}
+		return nil, fmt.Errorf("failed to client.Do after several retries.")
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	defer resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to io.ReadAll: %w", err)
+	}
+
+	return body, nil
 }
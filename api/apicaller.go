@@ -1,10 +1,17 @@
 package api
 
+import "errors"
+
 // Github REST Api caller
 // For detailed information, see the official documentations:
 // https://docs.github.com/ja/rest
 type ApiCaller interface {
 	ListPublicRepositories(userName string) ([]Repository, error)
 	ListRepositoriesForAuthenticatedUser() ([]Repository, error)
+	Languages(fullName string) (map[string]int, error)
 	WeeklyCommitActivity(fullName string) ([]CodeFrequency, error)
+	Participation(fullName string) (Participation, error)
 }
+
+// Error returned by backends which can not provide the requested data.
+var ErrNotSupported = errors.New("not supported by the backend")
@@ -0,0 +1,180 @@
+package api
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/kokoichi206/go-git-stats/api/internal/rest"
+	"github.com/kokoichi206/go-git-stats/util"
+)
+
+const (
+	// The installation token is refreshed this long before it expires.
+	refreshMargin = 5 * time.Minute
+	// Lifetime of the JWT (at most 10 minutes).
+	jwtLifetime = 9 * time.Minute
+	// Issued time of the JWT is set in the past for clock drift.
+	clockDrift = 60 * time.Second
+)
+
+// Installation access token of the GitHub App, which is refreshed before it expires.
+// For detailed information, see the official documentations:
+// https://docs.github.com/en/apps/creating-github-apps/authenticating-with-a-github-app/authenticating-as-a-github-app-installation
+type appToken struct {
+	config util.AppConfig
+	// Base URL of REST API.
+	baseURL string
+	client  *http.Client
+	now     func() time.Time
+
+	mutex     sync.Mutex
+	key       *rsa.PrivateKey
+	token     string
+	expiresAt time.Time
+}
+
+func newAppToken(config util.AppConfig, baseURL string, client *http.Client) *appToken {
+	return &appToken{
+		config:  config,
+		baseURL: baseURL,
+		client:  client,
+		now:     time.Now,
+	}
+}
+
+// Returns the installation access token, which is created (or refreshed) if needed.
+// It is safe to call concurrently.
+func (t *appToken) get() (string, time.Time, error) {
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if t.token != "" && t.now().Add(refreshMargin).Before(t.expiresAt) {
+		return t.token, t.expiresAt, nil
+	}
+
+	if t.key == nil {
+		key, err := loadPrivateKey(t.config.PrivateKeyPath)
+		if err != nil {
+			return "", time.Time{}, err
+		}
+		t.key = key
+	}
+
+	jwt, err := signJWT(t.key, strconv.FormatInt(t.config.ID, 10), t.now())
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	URL := fmt.Sprintf("%s/app/installations/%d/access_tokens", t.baseURL, t.config.InstallationID)
+	req, err := newRequest("POST", URL)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to http.NewRequest: %w", err)
+	}
+
+	// Set request header.
+	req.Header.Add("Accept", "application/vnd.github+json")
+	req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", jwt))
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to client.Do: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		return "", time.Time{}, fmt.Errorf("failed to create the installation access token: %w", rest.NewStatusError(resp))
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to io.ReadAll: %w", err)
+	}
+
+	var token struct {
+		Token     string    `json:"token"`
+		ExpiresAt time.Time `json:"expires_at"`
+	}
+	if err := json.Unmarshal(body, &token); err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to json.Unmarshal: %w", err)
+	}
+
+	t.token = token.Token
+	t.expiresAt = token.ExpiresAt
+	return t.token, t.expiresAt, nil
+}
+
+// Load the RSA private key of the GitHub App (PKCS #1 or PKCS #8 in PEM).
+func loadPrivateKey(path string) (*rsa.PrivateKey, error) {
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to ioutil.ReadFile: %w", err)
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM data found in the private key: %s", path)
+	}
+
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		key, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to x509.ParsePKCS1PrivateKey: %w", err)
+		}
+		return key, nil
+	case "PRIVATE KEY":
+		key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to x509.ParsePKCS8PrivateKey: %w", err)
+		}
+		rsaKey, ok := key.(*rsa.PrivateKey)
+		if !ok {
+			return nil, fmt.Errorf("the private key is not RSA: %s", path)
+		}
+		return rsaKey, nil
+	default:
+		return nil, fmt.Errorf("unsupported type of the private key: %s", block.Type)
+	}
+}
+
+// Sign the JWT of the GitHub App with RS256.
+// see: https://docs.github.com/en/apps/creating-github-apps/authenticating-with-a-github-app/generating-a-json-web-token-jwt-for-a-github-app
+func signJWT(key *rsa.PrivateKey, issuer string, now time.Time) (string, error) {
+
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT"})
+	if err != nil {
+		return "", fmt.Errorf("failed to json.Marshal: %w", err)
+	}
+	claims, err := json.Marshal(map[string]interface{}{
+		"iat": now.Add(-clockDrift).Unix(),
+		"exp": now.Add(jwtLifetime).Unix(),
+		"iss": issuer,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to json.Marshal: %w", err)
+	}
+
+	unsigned := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(claims)
+	hash := sha256.Sum256([]byte(unsigned))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, hash[:])
+	if err != nil {
+		return "", fmt.Errorf("failed to rsa.SignPKCS1v15: %w", err)
+	}
+
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
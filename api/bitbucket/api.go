@@ -0,0 +1,43 @@
+// Package bitbucket implements ApiCaller with Bitbucket Cloud and Bitbucket Server (Data Center).
+//
+// Bitbucket has no statistics like GitHub,
+// so weekly line changes are aggregated from the diffs of commits.
+package bitbucket
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/kokoichi206/go-git-stats/api/internal/rest"
+	"github.com/kokoichi206/go-git-stats/util"
+)
+
+// Call GET method with the credential of the provider and return the body of the response.
+// The token is sent as a password of basic authentication if the user name is set
+// (app passwords of Bitbucket Cloud), otherwise as a bearer token (access tokens).
+func get(client *http.Client, URL string, provider util.ProviderConfig) ([]byte, error) {
+
+	header := http.Header{}
+	header.Add("Accept", "application/json")
+	if provider.Token != "" {
+		if provider.Username != "" {
+			credential := base64.StdEncoding.EncodeToString([]byte(provider.Username + ":" + provider.Token))
+			header.Add("Authorization", fmt.Sprintf("Basic %s", credential))
+		} else {
+			header.Add("Authorization", fmt.Sprintf("Bearer %s", provider.Token))
+		}
+	}
+
+	body, _, err := rest.Get(client, URL, header)
+	return body, err
+}
+
+// Returns the URL with the query parameter appended.
+func withQuery(URL string, query string) string {
+	if strings.Contains(URL, "?") {
+		return URL + "&" + query
+	}
+	return URL + "?" + query
+}
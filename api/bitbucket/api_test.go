@@ -0,0 +1,369 @@
+package bitbucket_test
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/kokoichi206/go-git-stats/api"
+	"github.com/kokoichi206/go-git-stats/api/bitbucket"
+	"github.com/kokoichi206/go-git-stats/api/internal/resttest"
+	"github.com/kokoichi206/go-git-stats/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCloudListRepositories(t *testing.T) {
+
+	ts := NewTestServer()
+	defer ts.Close()
+
+	a := bitbucket.New(util.Config{
+		Bitbucket: util.ProviderConfig{BaseURL: ts.URL, Token: "kokoichi206token"},
+	})
+
+	testCases := []struct {
+		name      string
+		setup     func()
+		act       func() ([]api.Repository, error)
+		assertion func(t *testing.T, err error, repositories []api.Repository)
+	}{
+		{
+			name: "OK workspace with pagination",
+			setup: func() {
+				ts.Responses["/2.0/repositories/kokoichi206"] = resttest.Response{StatusCode: http.StatusOK, Body: mockCloudRepositoriesFirstPage}
+				ts.Responses["/2.0/repositories/kokoichi206?page=2"] = resttest.Response{StatusCode: http.StatusOK, Body: mockCloudRepositoriesLastPage}
+			},
+			act: func() ([]api.Repository, error) {
+				return a.ListPublicRepositories("kokoichi206")
+			},
+			assertion: func(t *testing.T, err error, repositories []api.Repository) {
+				require.NoError(t, err)
+				require.Equal(t, []api.Repository{
+					{ID: 1, Private: false, Name: "go-git-stats", FullName: "kokoichi206/go-git-stats", Language: "go"},
+					{ID: 2, Private: true, Name: "private-repo", FullName: "kokoichi206/private-repo", Language: ""},
+				}, repositories)
+
+				// The 'next' URL is followed as it is.
+				require.Equal(t, []string{
+					"/2.0/repositories/kokoichi206?pagelen=100",
+					"/2.0/repositories/kokoichi206?pagelen=1&page=2",
+				}, ts.Requests)
+				require.Equal(t, "Bearer kokoichi206token", ts.RequestHeader.Get("Authorization"))
+			},
+		},
+		{
+			name: "OK authenticated user",
+			setup: func() {
+				ts.Responses["/2.0/repositories"] = resttest.Response{StatusCode: http.StatusOK, Body: mockCloudRepositoriesLastPage}
+			},
+			act: a.ListRepositoriesForAuthenticatedUser,
+			assertion: func(t *testing.T, err error, repositories []api.Repository) {
+				require.NoError(t, err)
+				require.Equal(t, 1, len(repositories))
+				require.Equal(t, []string{"/2.0/repositories?role=member&pagelen=100"}, ts.Requests)
+			},
+		},
+		{
+			name: "Error Unauthorized",
+			setup: func() {
+				ts.Responses["/2.0/repositories"] = resttest.Response{StatusCode: http.StatusUnauthorized}
+			},
+			act: a.ListRepositoriesForAuthenticatedUser,
+			assertion: func(t *testing.T, err error, repositories []api.Repository) {
+				require.Error(t, err)
+				require.Equal(t, "failed to client.Do: StatusCode is 401", err.Error())
+				require.Nil(t, repositories)
+				require.Equal(t, 1, len(ts.Requests))
+			},
+		},
+		{
+			name: "Unmarshal failed",
+			setup: func() {
+				ts.Responses["/2.0/repositories"] = resttest.Response{StatusCode: http.StatusOK, Body: `{"values": {"name": "go-git-stats"}}`}
+			},
+			act: a.ListRepositoriesForAuthenticatedUser,
+			assertion: func(t *testing.T, err error, repositories []api.Repository) {
+				require.Error(t, err)
+				require.True(t, strings.Contains(err.Error(), "json.Unmarshal"))
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			tc.setup()
+			defer ts.Reset()
+
+			// Act
+			repositories, err := tc.act()
+
+			// Assert
+			tc.assertion(t, err, repositories)
+		})
+	}
+}
+
+func TestCloudWeeklyCommitActivity(t *testing.T) {
+
+	ts := NewTestServer()
+	defer ts.Close()
+
+	// App password
+	a := bitbucket.New(util.Config{
+		Bitbucket: util.ProviderConfig{BaseURL: ts.URL + "/", Token: "app-password", Username: "kokoichi206"},
+	})
+
+	testCases := []struct {
+		name      string
+		setup     func()
+		assertion func(t *testing.T, err error, frequencies []api.CodeFrequency)
+	}{
+		{
+			name: "OK",
+			setup: func() {
+				ts.Responses["/2.0/repositories/kokoichi206/go-git-stats"] = resttest.Response{StatusCode: http.StatusOK, Body: mockCloudRepository}
+				ts.Responses["/2.0/repositories/kokoichi206/go-git-stats/commits/main"] = resttest.Response{StatusCode: http.StatusOK, Body: mockCloudCommits}
+				ts.Responses["/2.0/repositories/kokoichi206/go-git-stats/diffstat/c2"] = resttest.Response{StatusCode: http.StatusOK, Body: mockCloudDiffStatFirstPage}
+				ts.Responses["/2.0/repositories/kokoichi206/go-git-stats/diffstat/c2?page=2"] = resttest.Response{StatusCode: http.StatusOK, Body: mockCloudDiffStatLastPage}
+				ts.Responses["/2.0/repositories/kokoichi206/go-git-stats/diffstat/c1"] = resttest.Response{StatusCode: http.StatusOK, Body: mockCloudDiffStatInitial}
+			},
+			assertion: func(t *testing.T, err error, frequencies []api.CodeFrequency) {
+				require.NoError(t, err)
+				require.Equal(t, []api.CodeFrequency{
+					{Time: 1659830400, Additions: 15, Deletions: -4},
+					{Time: 1659225600, Additions: 100, Deletions: 0},
+				}, frequencies)
+
+				// Commits of the main branch, and diffstat of the merge commit is not requested.
+				require.Equal(t, []string{
+					"/2.0/repositories/kokoichi206/go-git-stats",
+					"/2.0/repositories/kokoichi206/go-git-stats/commits/main?pagelen=100",
+					"/2.0/repositories/kokoichi206/go-git-stats/diffstat/c2?pagelen=100",
+					"/2.0/repositories/kokoichi206/go-git-stats/diffstat/c2?pagelen=1&page=2",
+					"/2.0/repositories/kokoichi206/go-git-stats/diffstat/c1?pagelen=100",
+				}, ts.Requests)
+				// base64 of 'kokoichi206:app-password'
+				require.Equal(t, "Basic a29rb2ljaGkyMDY6YXBwLXBhc3N3b3Jk", ts.RequestHeader.Get("Authorization"))
+			},
+		},
+		{
+			name: "OK without commits",
+			setup: func() {
+				ts.Responses["/2.0/repositories/kokoichi206/go-git-stats"] = resttest.Response{StatusCode: http.StatusOK, Body: mockCloudEmptyRepository}
+			},
+			assertion: func(t *testing.T, err error, frequencies []api.CodeFrequency) {
+				require.NoError(t, err)
+				require.Empty(t, frequencies)
+				require.Equal(t, []string{"/2.0/repositories/kokoichi206/go-git-stats"}, ts.Requests)
+			},
+		},
+		{
+			name: "Error diffstat Not Found",
+			setup: func() {
+				ts.Responses["/2.0/repositories/kokoichi206/go-git-stats"] = resttest.Response{StatusCode: http.StatusOK, Body: mockCloudRepository}
+				ts.Responses["/2.0/repositories/kokoichi206/go-git-stats/commits/main"] = resttest.Response{StatusCode: http.StatusOK, Body: mockCloudCommits}
+			},
+			assertion: func(t *testing.T, err error, frequencies []api.CodeFrequency) {
+				require.Error(t, err)
+				require.Equal(t, "failed to client.Do: StatusCode is 404: Not found", err.Error())
+				require.True(t, errors.Is(err, api.ErrNotFound))
+				require.Nil(t, frequencies)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			tc.setup()
+			defer ts.Reset()
+
+			// Act
+			frequencies, err := a.WeeklyCommitActivity("kokoichi206/go-git-stats")
+
+			// Assert
+			tc.assertion(t, err, frequencies)
+		})
+	}
+}
+
+func TestServerListRepositories(t *testing.T) {
+
+	ts := NewTestServer()
+	defer ts.Close()
+
+	a := bitbucket.NewServer(util.Config{
+		BitbucketServer: util.ProviderConfig{BaseURL: ts.URL + "/bitbucket", Token: "kokoichi206token"},
+	})
+
+	testCases := []struct {
+		name      string
+		setup     func()
+		act       func() ([]api.Repository, error)
+		assertion func(t *testing.T, err error, repositories []api.Repository)
+	}{
+		{
+			name: "OK project with pagination",
+			setup: func() {
+				ts.Responses["/bitbucket/rest/api/1.0/projects/KOKO/repos"] = resttest.Response{StatusCode: http.StatusOK, Body: mockServerRepositoriesFirstPage}
+				ts.Responses["/bitbucket/rest/api/1.0/projects/KOKO/repos?start=1"] = resttest.Response{StatusCode: http.StatusOK, Body: mockServerRepositoriesLastPage}
+			},
+			act: func() ([]api.Repository, error) {
+				return a.ListPublicRepositories("KOKO")
+			},
+			assertion: func(t *testing.T, err error, repositories []api.Repository) {
+				require.NoError(t, err)
+				require.Equal(t, []api.Repository{
+					{ID: 11, Private: false, Name: "go-git-stats", FullName: "KOKO/go-git-stats"},
+					{ID: 12, Private: true, Name: "Private Repo", FullName: "KOKO/private-repo"},
+				}, repositories)
+
+				require.Equal(t, []string{
+					"/bitbucket/rest/api/1.0/projects/KOKO/repos?start=0&limit=100",
+					"/bitbucket/rest/api/1.0/projects/KOKO/repos?start=1&limit=100",
+				}, ts.Requests)
+				require.Equal(t, "Bearer kokoichi206token", ts.RequestHeader.Get("Authorization"))
+			},
+		},
+		{
+			name: "OK personal repositories when the project is not found",
+			setup: func() {
+				ts.Responses["/bitbucket/rest/api/1.0/users/kokoichi206/repos"] = resttest.Response{StatusCode: http.StatusOK, Body: mockServerRepositoriesLastPage}
+			},
+			act: func() ([]api.Repository, error) {
+				return a.ListPublicRepositories("kokoichi206")
+			},
+			assertion: func(t *testing.T, err error, repositories []api.Repository) {
+				require.NoError(t, err)
+				require.Equal(t, 1, len(repositories))
+				require.Equal(t, []string{
+					"/bitbucket/rest/api/1.0/projects/kokoichi206/repos?start=0&limit=100",
+					"/bitbucket/rest/api/1.0/users/kokoichi206/repos?start=0&limit=100",
+				}, ts.Requests)
+			},
+		},
+		{
+			name: "OK authenticated user",
+			setup: func() {
+				ts.Responses["/bitbucket/rest/api/1.0/repos"] = resttest.Response{StatusCode: http.StatusOK, Body: mockServerRepositoriesLastPage}
+			},
+			act: a.ListRepositoriesForAuthenticatedUser,
+			assertion: func(t *testing.T, err error, repositories []api.Repository) {
+				require.NoError(t, err)
+				require.Equal(t, 1, len(repositories))
+				require.Equal(t, []string{"/bitbucket/rest/api/1.0/repos?permission=REPO_READ&start=0&limit=100"}, ts.Requests)
+			},
+		},
+		{
+			name: "Error after retry",
+			setup: func() {
+				ts.Responses["/bitbucket/rest/api/1.0/repos"] = resttest.Response{StatusCode: http.StatusServiceUnavailable}
+			},
+			act: a.ListRepositoriesForAuthenticatedUser,
+			assertion: func(t *testing.T, err error, repositories []api.Repository) {
+				require.Error(t, err)
+				require.Equal(t, "failed to client.Do after several retries.", err.Error())
+				require.Equal(t, 3, len(ts.Requests))
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			tc.setup()
+			defer ts.Reset()
+
+			// Act
+			repositories, err := tc.act()
+
+			// Assert
+			tc.assertion(t, err, repositories)
+		})
+	}
+}
+
+func TestServerWeeklyCommitActivity(t *testing.T) {
+
+	ts := NewTestServer()
+	defer ts.Close()
+
+	a := bitbucket.NewServer(util.Config{BitbucketServer: util.ProviderConfig{BaseURL: ts.URL}})
+
+	testCases := []struct {
+		name      string
+		setup     func()
+		fullName  string
+		assertion func(t *testing.T, err error, frequencies []api.CodeFrequency)
+	}{
+		{
+			name: "OK",
+			setup: func() {
+				ts.Responses["/rest/api/1.0/projects/KOKO/repos/go-git-stats/commits"] = resttest.Response{StatusCode: http.StatusOK, Body: mockServerCommits}
+				ts.Responses["/rest/api/1.0/projects/KOKO/repos/go-git-stats/commits/c2/diff"] = resttest.Response{StatusCode: http.StatusOK, Body: mockServerDiffFeature}
+				ts.Responses["/rest/api/1.0/projects/KOKO/repos/go-git-stats/commits/c1/diff"] = resttest.Response{StatusCode: http.StatusOK, Body: mockServerDiffInitial}
+			},
+			fullName: "KOKO/go-git-stats",
+			assertion: func(t *testing.T, err error, frequencies []api.CodeFrequency) {
+				require.NoError(t, err)
+				require.Equal(t, []api.CodeFrequency{
+					{Time: 1659830400, Additions: 2, Deletions: -1},
+					{Time: 1659225600, Additions: 3, Deletions: 0},
+				}, frequencies)
+
+				require.Equal(t, []string{
+					"/rest/api/1.0/projects/KOKO/repos/go-git-stats/commits?start=0&limit=100",
+					"/rest/api/1.0/projects/KOKO/repos/go-git-stats/commits/c2/diff?contextLines=0",
+					"/rest/api/1.0/projects/KOKO/repos/go-git-stats/commits/c1/diff?contextLines=0",
+				}, ts.Requests)
+				// No token
+				require.Equal(t, "", ts.RequestHeader.Get("Authorization"))
+			},
+		},
+		{
+			name:     "Error invalid name",
+			setup:    func() {},
+			fullName: "go-git-stats",
+			assertion: func(t *testing.T, err error, frequencies []api.CodeFrequency) {
+				require.Error(t, err)
+				require.Equal(t, "invalid repository name of Bitbucket Server: 'go-git-stats' (expected '<project>/<repository>')", err.Error())
+				require.Nil(t, ts.Requests)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			tc.setup()
+			defer ts.Reset()
+
+			// Act
+			frequencies, err := a.WeeklyCommitActivity(tc.fullName)
+
+			// Assert
+			tc.assertion(t, err, frequencies)
+		})
+	}
+}
+
+func TestNotSupported(t *testing.T) {
+
+	for _, a := range []api.ApiCaller{bitbucket.New(util.Config{}), bitbucket.NewServer(util.Config{})} {
+		_, err := a.Languages("KOKO/go-git-stats")
+		require.True(t, errors.Is(err, api.ErrNotSupported))
+
+		_, err = a.Participation("KOKO/go-git-stats")
+		require.True(t, errors.Is(err, api.ErrNotSupported))
+	}
+}
@@ -0,0 +1,216 @@
+package bitbucket
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/kokoichi206/go-git-stats/api"
+	"github.com/kokoichi206/go-git-stats/util"
+)
+
+// Number of items per page (maximum of most endpoints of Bitbucket Cloud).
+const cloudPageLen = 100
+
+// struct that implements ApiCaller with Bitbucket Cloud REST API 2.0.
+// FullName of each repository is '<workspace>/<repo_slug>'.
+// For detailed information, see the official documentations:
+// https://developer.atlassian.com/cloud/bitbucket/rest/intro/
+type Api struct {
+	config util.Config
+	client *http.Client
+}
+
+// Returns the ApiCaller of the Bitbucket Cloud REST API 2.0.
+// The options of the GitHub API (e.g. the user agent and the logger) apply to its requests.
+func New(config util.Config, opts ...api.Option) api.ApiCaller {
+	return &Api{
+		config: config,
+		client: api.NewHTTPClient(config, opts...),
+	}
+}
+
+// Paginated response of Bitbucket Cloud.
+// See documentation:
+// https://developer.atlassian.com/cloud/bitbucket/rest/intro/#pagination
+type cloudPage struct {
+	Values json.RawMessage `json:"values"`
+	// URL of the next page, which is empty at the last page.
+	Next string `json:"next"`
+}
+
+// Repository of Bitbucket Cloud.
+type cloudRepository struct {
+	Name      string `json:"name"`
+	FullName  string `json:"full_name"`
+	IsPrivate bool   `json:"is_private"`
+	Language  string `json:"language"`
+	// Main branch, which is nil for repositories without commits.
+	MainBranch *struct {
+		Name string `json:"name"`
+	} `json:"mainbranch"`
+}
+
+// Commit of Bitbucket Cloud.
+type cloudCommit struct {
+	Hash    string    `json:"hash"`
+	Date    time.Time `json:"date"`
+	Parents []struct {
+		Hash string `json:"hash"`
+	} `json:"parents"`
+}
+
+// Changes of a file in the diffstat of Bitbucket Cloud.
+type cloudDiffStat struct {
+	LinesAdded   int `json:"lines_added"`
+	LinesRemoved int `json:"lines_removed"`
+}
+
+// Base URL of the REST API.
+func (a *Api) baseURL() string {
+	return strings.TrimSuffix(a.config.Bitbucket.BaseURL, "/") + "/2.0"
+}
+
+// Call GET method for every page of the list and pass the values of each page to the callback.
+// Pages are followed by the 'next' URL in the response.
+func (a *Api) getAllPages(URL string, callback func(values []byte) error) error {
+
+	next := withQuery(URL, fmt.Sprintf("pagelen=%d", cloudPageLen))
+	for next != "" {
+		body, err := get(a.client, next, a.config.Bitbucket)
+		if err != nil {
+			return err
+		}
+
+		var page cloudPage
+		if err := json.Unmarshal(body, &page); err != nil {
+			return fmt.Errorf("failed to json.Unmarshal: %w", err)
+		}
+		if err := callback(page.Values); err != nil {
+			return err
+		}
+
+		next = page.Next
+	}
+
+	return nil
+}
+
+// Lists repositories of a workspace (a user or a team).
+// See documentation:
+// https://developer.atlassian.com/cloud/bitbucket/rest/api-group-repositories/#api-repositories-workspace-get
+func (a *Api) ListPublicRepositories(userName string) ([]api.Repository, error) {
+	return a.listRepositories(fmt.Sprintf("%s/repositories/%s", a.baseURL(), url.PathEscape(userName)))
+}
+
+// Lists repositories which the authenticated user is a member of.
+// Config must have the Bitbucket credential.
+// See documentation:
+// https://developer.atlassian.com/cloud/bitbucket/rest/api-group-repositories/#api-repositories-get
+func (a *Api) ListRepositoriesForAuthenticatedUser() ([]api.Repository, error) {
+	return a.listRepositories(fmt.Sprintf("%s/repositories?role=member", a.baseURL()))
+}
+
+// Bitbucket Cloud shows only the primary language of repositories.
+func (a *Api) Languages(fullName string) (map[string]int, error) {
+	return nil, fmt.Errorf("languages of Bitbucket: %w", api.ErrNotSupported)
+}
+
+// Get the weekly commit activity of the repository.
+// The line changes of all commits (except merges) of the main branch are aggregated
+// from the diffstat of each commit.
+// The main branch is given as the revision, since commits without it are of all branches.
+// See documentation:
+// https://developer.atlassian.com/cloud/bitbucket/rest/api-group-repositories/#api-repositories-workspace-repo-slug-get
+// https://developer.atlassian.com/cloud/bitbucket/rest/api-group-commits/#api-repositories-workspace-repo-slug-commits-revision-get
+// https://developer.atlassian.com/cloud/bitbucket/rest/api-group-commits/#api-repositories-workspace-repo-slug-diffstat-spec-get
+func (a *Api) WeeklyCommitActivity(fullName string) ([]api.CodeFrequency, error) {
+
+	body, err := get(a.client, fmt.Sprintf("%s/repositories/%s", a.baseURL(), fullName), a.config.Bitbucket)
+	if err != nil {
+		return nil, err
+	}
+	var repository cloudRepository
+	if err := json.Unmarshal(body, &repository); err != nil {
+		return nil, fmt.Errorf("failed to json.Unmarshal: %w", err)
+	}
+	if repository.MainBranch == nil {
+		return api.WeeklyCodeFrequency(nil), nil
+	}
+
+	var commits []cloudCommit
+	URL := fmt.Sprintf("%s/repositories/%s/commits/%s", a.baseURL(), fullName, url.PathEscape(repository.MainBranch.Name))
+	err = a.getAllPages(URL, func(values []byte) error {
+		var page []cloudCommit
+		if err := json.Unmarshal(values, &page); err != nil {
+			return fmt.Errorf("failed to json.Unmarshal: %w", err)
+		}
+		commits = append(commits, page...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var stats []api.CommitStat
+	for _, c := range commits {
+		if len(c.Parents) > 1 {
+			continue
+		}
+
+		stat := api.CommitStat{Time: c.Date}
+		err := a.getAllPages(fmt.Sprintf("%s/repositories/%s/diffstat/%s", a.baseURL(), fullName, c.Hash), func(values []byte) error {
+			var files []cloudDiffStat
+			if err := json.Unmarshal(values, &files); err != nil {
+				return fmt.Errorf("failed to json.Unmarshal: %w", err)
+			}
+			for _, f := range files {
+				stat.Additions += f.LinesAdded
+				stat.Deletions += f.LinesRemoved
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		stats = append(stats, stat)
+	}
+
+	return api.WeeklyCodeFrequency(stats), nil
+}
+
+// Bitbucket has no owner of commits like GitHub statistics.
+func (a *Api) Participation(fullName string) (api.Participation, error) {
+	return api.Participation{}, fmt.Errorf("participation of Bitbucket: %w", api.ErrNotSupported)
+}
+
+// List all repositories of the URL.
+// Repositories of Bitbucket Cloud have no numeric ID, so they are numbered in order.
+func (a *Api) listRepositories(URL string) ([]api.Repository, error) {
+
+	var repositories []api.Repository
+	err := a.getAllPages(URL, func(values []byte) error {
+		var page []cloudRepository
+		if err := json.Unmarshal(values, &page); err != nil {
+			return fmt.Errorf("failed to json.Unmarshal: %w", err)
+		}
+		for _, r := range page {
+			repositories = append(repositories, api.Repository{
+				ID:       len(repositories) + 1,
+				Private:  r.IsPrivate,
+				Name:     r.Name,
+				FullName: r.FullName,
+				Language: r.Language,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return repositories, nil
+}
@@ -0,0 +1,223 @@
+package bitbucket_test
+
+// First page of repositories of Bitbucket Cloud.
+const mockCloudRepositoriesFirstPage = `{
+  "pagelen": 1,
+  "size": 2,
+  "page": 1,
+  "next": "{{URL}}/2.0/repositories/kokoichi206?pagelen=1&page=2",
+  "values": [
+    {
+      "type": "repository",
+      "uuid": "{1b1f1f6e-0000-0000-0000-000000000001}",
+      "name": "go-git-stats",
+      "full_name": "kokoichi206/go-git-stats",
+      "is_private": false,
+      "language": "go",
+      "mainbranch": {"name": "main", "type": "branch"}
+    }
+  ]
+}`
+
+// Last page of repositories of Bitbucket Cloud.
+const mockCloudRepositoriesLastPage = `{
+  "pagelen": 1,
+  "size": 2,
+  "page": 2,
+  "previous": "{{URL}}/2.0/repositories/kokoichi206?pagelen=1&page=1",
+  "values": [
+    {
+      "type": "repository",
+      "uuid": "{1b1f1f6e-0000-0000-0000-000000000002}",
+      "name": "private-repo",
+      "full_name": "kokoichi206/private-repo",
+      "is_private": true,
+      "language": ""
+    }
+  ]
+}`
+
+// Repository of Bitbucket Cloud.
+const mockCloudRepository = `{
+  "type": "repository",
+  "name": "go-git-stats",
+  "full_name": "kokoichi206/go-git-stats",
+  "is_private": false,
+  "language": "go",
+  "mainbranch": {"name": "main", "type": "branch"}
+}`
+
+// Repository without commits of Bitbucket Cloud.
+const mockCloudEmptyRepository = `{
+  "type": "repository",
+  "name": "go-git-stats",
+  "full_name": "kokoichi206/go-git-stats",
+  "is_private": false,
+  "language": "",
+  "mainbranch": null
+}`
+
+// 3 commits including a merge commit of Bitbucket Cloud.
+const mockCloudCommits = `{
+  "pagelen": 30,
+  "values": [
+    {
+      "type": "commit",
+      "hash": "c3",
+      "date": "2022-08-10T09:00:00+09:00",
+      "message": "Merge branch 'feature'",
+      "parents": [{"hash": "c2"}, {"hash": "c1"}]
+    },
+    {
+      "type": "commit",
+      "hash": "c2",
+      "date": "2022-08-10T09:00:00+09:00",
+      "message": "Add feature",
+      "parents": [{"hash": "c1"}]
+    },
+    {
+      "type": "commit",
+      "hash": "c1",
+      "date": "2022-08-03T12:00:00+00:00",
+      "message": "Initial commit",
+      "parents": []
+    }
+  ]
+}`
+
+// Diffstat of c2 (first page).
+const mockCloudDiffStatFirstPage = `{
+  "pagelen": 1,
+  "next": "{{URL}}/2.0/repositories/kokoichi206/go-git-stats/diffstat/c2?pagelen=1&page=2",
+  "values": [
+    {"type": "diffstat", "status": "modified", "lines_added": 10, "lines_removed": 4}
+  ]
+}`
+
+// Diffstat of c2 (last page).
+const mockCloudDiffStatLastPage = `{
+  "pagelen": 1,
+  "values": [
+    {"type": "diffstat", "status": "added", "lines_added": 5, "lines_removed": 0}
+  ]
+}`
+
+// Diffstat of c1.
+const mockCloudDiffStatInitial = `{
+  "pagelen": 500,
+  "values": [
+    {"type": "diffstat", "status": "added", "lines_added": 100, "lines_removed": 0}
+  ]
+}`
+
+// First page of repositories of a project of Bitbucket Server.
+const mockServerRepositoriesFirstPage = `{
+  "size": 1,
+  "limit": 1,
+  "start": 0,
+  "isLastPage": false,
+  "nextPageStart": 1,
+  "values": [
+    {
+      "id": 11,
+      "slug": "go-git-stats",
+      "name": "go-git-stats",
+      "public": true,
+      "project": {"key": "KOKO", "id": 1, "name": "kokoichi"}
+    }
+  ]
+}`
+
+// Last page of repositories of a project of Bitbucket Server.
+const mockServerRepositoriesLastPage = `{
+  "size": 1,
+  "limit": 1,
+  "start": 1,
+  "isLastPage": true,
+  "values": [
+    {
+      "id": 12,
+      "slug": "private-repo",
+      "name": "Private Repo",
+      "public": false,
+      "project": {"key": "KOKO", "id": 1, "name": "kokoichi"}
+    }
+  ]
+}`
+
+// 3 commits including a merge commit of Bitbucket Server.
+const mockServerCommits = `{
+  "size": 3,
+  "limit": 100,
+  "start": 0,
+  "isLastPage": true,
+  "values": [
+    {
+      "id": "c3",
+      "authorTimestamp": 1660089600000,
+      "message": "Merge branch 'feature'",
+      "parents": [{"id": "c2"}, {"id": "c1"}]
+    },
+    {
+      "id": "c2",
+      "authorTimestamp": 1660089600000,
+      "message": "Add feature",
+      "parents": [{"id": "c1"}]
+    },
+    {
+      "id": "c1",
+      "authorTimestamp": 1659528000000,
+      "message": "Initial commit",
+      "parents": []
+    }
+  ]
+}`
+
+// Diff of c2 of Bitbucket Server.
+const mockServerDiffFeature = `{
+  "fromHash": "c1",
+  "toHash": "c2",
+  "contextLines": 0,
+  "diffs": [
+    {
+      "source": {"toString": "main.go"},
+      "destination": {"toString": "main.go"},
+      "hunks": [
+        {
+          "sourceLine": 1,
+          "destinationLine": 1,
+          "segments": [
+            {"type": "REMOVED", "lines": [{"line": "old", "source": 1, "destination": 1}]},
+            {"type": "ADDED", "lines": [
+              {"line": "new1", "source": 2, "destination": 1},
+              {"line": "new2", "source": 2, "destination": 2}
+            ]}
+          ]
+        }
+      ]
+    }
+  ]
+}`
+
+// Diff of c1 of Bitbucket Server.
+const mockServerDiffInitial = `{
+  "fromHash": null,
+  "toHash": "c1",
+  "contextLines": 0,
+  "diffs": [
+    {
+      "destination": {"toString": "README.md"},
+      "hunks": [
+        {
+          "segments": [
+            {"type": "ADDED", "lines": [
+              {"line": "# go-git-stats"},
+              {"line": ""},
+              {"line": "Go git stats cli"}
+            ]}
+          ]
+        }
+      ]
+    }
+  ]
+}`
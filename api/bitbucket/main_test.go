@@ -0,0 +1,22 @@
+package bitbucket_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/kokoichi206/go-git-stats/api/internal/resttest"
+)
+
+// Stand-in server of Bitbucket which responds by the escaped path and page.
+func NewTestServer() *resttest.Server {
+	return resttest.NewServer(`{"type": "error", "error": {"message": "Not found"}}`,
+		// Bitbucket Cloud
+		resttest.Page{Param: "page", First: "1"},
+		// Bitbucket Server
+		resttest.Page{Param: "start", First: "0"},
+	)
+}
+
+func TestMain(m *testing.M) {
+	os.Exit(m.Run())
+}
@@ -0,0 +1,239 @@
+package bitbucket
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/kokoichi206/go-git-stats/api"
+	"github.com/kokoichi206/go-git-stats/api/internal/rest"
+	"github.com/kokoichi206/go-git-stats/util"
+)
+
+// Number of items per page.
+const serverLimit = 100
+
+// struct that implements ApiCaller with Bitbucket Server (and Data Center) REST API 1.0.
+// FullName of each repository is '<project key>/<repository slug>'.
+// For detailed information, see the official documentations:
+// https://developer.atlassian.com/server/bitbucket/rest/
+type ServerApi struct {
+	config util.Config
+	client *http.Client
+}
+
+// Returns the ApiCaller of the Bitbucket Server REST API.
+// The options of the GitHub API (e.g. the user agent and the logger) apply to its requests.
+func NewServer(config util.Config, opts ...api.Option) api.ApiCaller {
+	return &ServerApi{
+		config: config,
+		client: api.NewHTTPClient(config, opts...),
+	}
+}
+
+// Paged response of Bitbucket Server.
+// See documentation:
+// https://developer.atlassian.com/server/bitbucket/rest/v811/intro/#paged-apis
+type serverPage struct {
+	Values        json.RawMessage `json:"values"`
+	IsLastPage    bool            `json:"isLastPage"`
+	NextPageStart int             `json:"nextPageStart"`
+}
+
+// Repository of Bitbucket Server.
+type serverRepository struct {
+	ID      int    `json:"id"`
+	Slug    string `json:"slug"`
+	Name    string `json:"name"`
+	Public  bool   `json:"public"`
+	Project struct {
+		Key string `json:"key"`
+	} `json:"project"`
+}
+
+// Commit of Bitbucket Server.
+type serverCommit struct {
+	ID string `json:"id"`
+	// Milliseconds since the epoch.
+	AuthorTimestamp int64 `json:"authorTimestamp"`
+	Parents         []struct {
+		ID string `json:"id"`
+	} `json:"parents"`
+}
+
+// Diff of a commit of Bitbucket Server.
+type serverDiff struct {
+	Diffs []struct {
+		Hunks []struct {
+			Segments []struct {
+				// ADDED, REMOVED or CONTEXT
+				Type  string            `json:"type"`
+				Lines []json.RawMessage `json:"lines"`
+			} `json:"segments"`
+		} `json:"hunks"`
+	} `json:"diffs"`
+}
+
+// Base URL of the REST API.
+func (a *ServerApi) baseURL() string {
+	return strings.TrimSuffix(a.config.BitbucketServer.BaseURL, "/") + "/rest/api/1.0"
+}
+
+// Call GET method for every page of the list and pass the values of each page to the callback.
+// Pages are followed by 'nextPageStart' until 'isLastPage'.
+func (a *ServerApi) getAllPages(URL string, callback func(values []byte) error) error {
+
+	start := 0
+	for {
+		body, err := get(a.client, withQuery(URL, fmt.Sprintf("start=%d&limit=%d", start, serverLimit)), a.config.BitbucketServer)
+		if err != nil {
+			return err
+		}
+
+		var page serverPage
+		if err := json.Unmarshal(body, &page); err != nil {
+			return fmt.Errorf("failed to json.Unmarshal: %w", err)
+		}
+		if err := callback(page.Values); err != nil {
+			return err
+		}
+
+		if page.IsLastPage {
+			return nil
+		}
+		start = page.NextPageStart
+	}
+}
+
+// Lists repositories of a project, or personal repositories of a user if the project is not found.
+// See documentation:
+// https://developer.atlassian.com/server/bitbucket/rest/v811/api-group-project/#api-api-latest-projects-projectkey-repos-get
+// https://developer.atlassian.com/server/bitbucket/rest/v811/api-group-repository/#api-api-latest-users-userslug-repos-get
+func (a *ServerApi) ListPublicRepositories(userName string) ([]api.Repository, error) {
+
+	repositories, err := a.listRepositories(fmt.Sprintf("%s/projects/%s/repos", a.baseURL(), url.PathEscape(userName)))
+	if rest.IsStatus(err, http.StatusNotFound) {
+		return a.listRepositories(fmt.Sprintf("%s/users/%s/repos", a.baseURL(), url.PathEscape(userName)))
+	}
+
+	return repositories, err
+}
+
+// Lists repositories which the authenticated user can read.
+// Config must have the Bitbucket Server access token.
+// See documentation:
+// https://developer.atlassian.com/server/bitbucket/rest/v811/api-group-repository/#api-api-latest-repos-get
+func (a *ServerApi) ListRepositoriesForAuthenticatedUser() ([]api.Repository, error) {
+	return a.listRepositories(fmt.Sprintf("%s/repos?permission=REPO_READ", a.baseURL()))
+}
+
+// Bitbucket Server has no languages of repositories.
+func (a *ServerApi) Languages(fullName string) (map[string]int, error) {
+	return nil, fmt.Errorf("languages of Bitbucket Server: %w", api.ErrNotSupported)
+}
+
+// Get the weekly commit activity of the repository.
+// The line changes of all commits (except merges) of the default branch are aggregated
+// by counting added and removed lines in the diff of each commit.
+// See documentation:
+// https://developer.atlassian.com/server/bitbucket/rest/v811/api-group-repository/#api-api-latest-projects-projectkey-repos-repositoryslug-commits-get
+// https://developer.atlassian.com/server/bitbucket/rest/v811/api-group-repository/#api-api-latest-projects-projectkey-repos-repositoryslug-commits-commitid-diff-get
+func (a *ServerApi) WeeklyCommitActivity(fullName string) ([]api.CodeFrequency, error) {
+
+	repositoryURL, err := a.repositoryURL(fullName)
+	if err != nil {
+		return nil, err
+	}
+
+	var commits []serverCommit
+	err = a.getAllPages(fmt.Sprintf("%s/commits", repositoryURL), func(values []byte) error {
+		var page []serverCommit
+		if err := json.Unmarshal(values, &page); err != nil {
+			return fmt.Errorf("failed to json.Unmarshal: %w", err)
+		}
+		commits = append(commits, page...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var stats []api.CommitStat
+	for _, c := range commits {
+		if len(c.Parents) > 1 {
+			continue
+		}
+
+		// Context lines are not needed to count changes.
+		body, err := get(a.client, fmt.Sprintf("%s/commits/%s/diff?contextLines=0", repositoryURL, c.ID), a.config.BitbucketServer)
+		if err != nil {
+			return nil, err
+		}
+		var diff serverDiff
+		if err := json.Unmarshal(body, &diff); err != nil {
+			return nil, fmt.Errorf("failed to json.Unmarshal: %w", err)
+		}
+
+		stat := api.CommitStat{Time: time.Unix(0, c.AuthorTimestamp*int64(time.Millisecond))}
+		for _, d := range diff.Diffs {
+			for _, h := range d.Hunks {
+				for _, s := range h.Segments {
+					switch s.Type {
+					case "ADDED":
+						stat.Additions += len(s.Lines)
+					case "REMOVED":
+						stat.Deletions += len(s.Lines)
+					}
+				}
+			}
+		}
+		stats = append(stats, stat)
+	}
+
+	return api.WeeklyCodeFrequency(stats), nil
+}
+
+// Bitbucket has no owner of commits like GitHub statistics.
+func (a *ServerApi) Participation(fullName string) (api.Participation, error) {
+	return api.Participation{}, fmt.Errorf("participation of Bitbucket Server: %w", api.ErrNotSupported)
+}
+
+// Returns the URL of the repository from the FullName ('<project key>/<repository slug>').
+func (a *ServerApi) repositoryURL(fullName string) (string, error) {
+
+	parts := strings.Split(fullName, "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", fmt.Errorf("invalid repository name of Bitbucket Server: '%s' (expected '<project>/<repository>')", fullName)
+	}
+
+	return fmt.Sprintf("%s/projects/%s/repos/%s", a.baseURL(), url.PathEscape(parts[0]), url.PathEscape(parts[1])), nil
+}
+
+// List all repositories of the URL.
+func (a *ServerApi) listRepositories(URL string) ([]api.Repository, error) {
+
+	var repositories []api.Repository
+	err := a.getAllPages(URL, func(values []byte) error {
+		var page []serverRepository
+		if err := json.Unmarshal(values, &page); err != nil {
+			return fmt.Errorf("failed to json.Unmarshal: %w", err)
+		}
+		for _, r := range page {
+			repositories = append(repositories, api.Repository{
+				ID:       r.ID,
+				Private:  !r.Public,
+				Name:     r.Name,
+				FullName: fmt.Sprintf("%s/%s", r.Project.Key, r.Slug),
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return repositories, nil
+}
@@ -601,3 +601,24 @@ const codeFrequenciesUnmarshalError = `[
 	  -813
 	]
 ]`
+
+const mockParticipation = `{
+	"all": [
+	  11,
+	  21,
+	  15,
+	  2
+	],
+	"owner": [
+	  3,
+	  2,
+	  3,
+	  0
+	]
+}`
+
+const mockLanguages = `{
+  "Go": 40500,
+  "Shell": 1520,
+  "Makefile": 380
+}`
@@ -0,0 +1,25 @@
+package api
+
+import "github.com/kokoichi206/go-git-stats/api/internal/rest"
+
+// Errors of responses, which are matched with errors.Is.
+// Details (e.g. the URL and the error message of the body) are available with errors.As and *StatusError,
+// and the reset time of the rate limit with *RateLimitError.
+var (
+	// 404, or the repository is private and the token can not access it.
+	ErrNotFound = rest.ErrNotFound
+	// 401, the token is invalid or expired.
+	ErrUnauthorized = rest.ErrUnauthorized
+	// 403 except the rate limit, the token is not allowed to access the resource.
+	ErrForbidden = rest.ErrForbidden
+	// 403 or 429 by the rate limit.
+	ErrRateLimited = rest.ErrRateLimited
+	// 202 of the statistics, which GitHub is still computing.
+	ErrStatsPending = rest.ErrStatsPending
+)
+
+// Error of the response with unexpected status code.
+type StatusError = rest.StatusError
+
+// Error of the response whose rate limit is exceeded.
+type RateLimitError = rest.RateLimitError
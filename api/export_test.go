@@ -1,6 +1,10 @@
 package api
 
-import "github.com/kokoichi206/go-git-stats/util"
+import (
+	"time"
+
+	"github.com/kokoichi206/go-git-stats/util"
+)
 
 func ExportNewApi(config util.Config) *Api {
 	return New(config).(*Api)
@@ -13,3 +17,25 @@ func (a *Api) ExportGetConfig() util.Config {
 func (a *Api) ExportSetURL(url string) {
 	a.config.ApiBaseURL = url
 }
+
+func (a *Api) ExportSetNow(now func() time.Time) {
+	a.app.now = now
+}
+
+func (a *Api) ExportSetPoolClock(now func() time.Time, after func(time.Duration) <-chan time.Time) {
+	a.pool.now = now
+	a.pool.after = after
+}
+
+func (a *Api) ExportSetLogClock(now func() time.Time) {
+	a.log.now = now
+}
+
+// Shorten the wait for statistics being computed, and returns the function to restore it.
+func ExportSetStatsWait(wait time.Duration) func() {
+	old := statsWait
+	statsWait = wait
+	return func() {
+		statsWait = old
+	}
+}
@@ -0,0 +1,61 @@
+package api
+
+import (
+	"sort"
+	"time"
+)
+
+// Line changes of a single commit.
+// Backends which can not use GitHub statistics aggregate these into CodeFrequency.
+type CommitStat struct {
+	// Canonical author of the commit.
+	Author    string
+	Time      time.Time
+	Additions int
+	Deletions int
+}
+
+// Returns the start (Sunday 00:00 UTC) of the week containing t,
+// which is the same week boundary GitHub statistics use.
+func WeekStart(t time.Time) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	return day.AddDate(0, 0, -int(day.Weekday()))
+}
+
+// Returns the start of the i-th week of participation (oldest first) of the weeks until now.
+// Weeks of participation are rolling 7 days until today (00:00 UTC) like GitHub statistics,
+// not the weeks from Sunday.
+func ParticipationWeekStart(now time.Time, i, weeks int) time.Time {
+	now = now.UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	return today.AddDate(0, 0, -7*(weeks-i))
+}
+
+// Aggregate commit stats into weekly CodeFrequency
+// in the same shape as WeeklyCommitActivity returns:
+// newest week first, and deletions as negative numbers.
+func WeeklyCodeFrequency(stats []CommitStat) []CodeFrequency {
+
+	weeks := make(map[int64]*CodeFrequency)
+	for _, s := range stats {
+		week := WeekStart(s.Time).Unix()
+		cf, ok := weeks[week]
+		if !ok {
+			cf = &CodeFrequency{Time: int(week)}
+			weeks[week] = cf
+		}
+		cf.Additions += s.Additions
+		cf.Deletions -= s.Deletions
+	}
+
+	codeFreqs := make([]CodeFrequency, 0, len(weeks))
+	for _, cf := range weeks {
+		codeFreqs = append(codeFreqs, *cf)
+	}
+	sort.Slice(codeFreqs, func(i, j int) bool {
+		return codeFreqs[i].Time > codeFreqs[j].Time
+	})
+
+	return codeFreqs
+}
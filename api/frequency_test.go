@@ -0,0 +1,58 @@
+package api_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kokoichi206/go-git-stats/api"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWeekStart(t *testing.T) {
+
+	// Wednesday
+	wednesday := time.Date(2022, 8, 10, 15, 4, 5, 0, time.UTC)
+	require.Equal(t, time.Date(2022, 8, 7, 0, 0, 0, 0, time.UTC), api.WeekStart(wednesday))
+
+	// Sunday is the first day of the week
+	sunday := time.Date(2022, 8, 7, 0, 0, 0, 0, time.UTC)
+	require.Equal(t, sunday, api.WeekStart(sunday))
+
+	// Other time zones are converted to UTC
+	jst := time.FixedZone("JST", 9*60*60)
+	require.Equal(t, time.Date(2022, 7, 31, 0, 0, 0, 0, time.UTC), api.WeekStart(time.Date(2022, 8, 7, 8, 0, 0, 0, jst)))
+}
+
+func TestParticipationWeekStart(t *testing.T) {
+
+	// Wednesday
+	now := time.Date(2022, 8, 10, 15, 4, 5, 0, time.UTC)
+
+	// Rolling weeks until today, not the weeks from Sunday
+	require.Equal(t, time.Date(2022, 8, 3, 0, 0, 0, 0, time.UTC), api.ParticipationWeekStart(now, 51, 52))
+	require.Equal(t, time.Date(2021, 8, 11, 0, 0, 0, 0, time.UTC), api.ParticipationWeekStart(now, 0, 52))
+
+	// Other time zones are converted to UTC
+	jst := time.FixedZone("JST", 9*60*60)
+	require.Equal(t, time.Date(2022, 8, 2, 0, 0, 0, 0, time.UTC), api.ParticipationWeekStart(time.Date(2022, 8, 10, 8, 0, 0, 0, jst), 51, 52))
+}
+
+func TestWeeklyCodeFrequency(t *testing.T) {
+
+	stats := []api.CommitStat{
+		{Time: time.Date(2022, 8, 1, 0, 0, 0, 0, time.UTC), Additions: 10, Deletions: 1},
+		{Time: time.Date(2022, 8, 10, 0, 0, 0, 0, time.UTC), Additions: 100, Deletions: 20},
+		{Time: time.Date(2022, 8, 6, 23, 59, 59, 0, time.UTC), Additions: 5, Deletions: 0},
+		{Time: time.Date(2022, 8, 13, 0, 0, 0, 0, time.UTC), Additions: 1, Deletions: 2},
+	}
+
+	frequencies := api.WeeklyCodeFrequency(stats)
+
+	// Newest week first, and deletions are negative like GitHub statistics.
+	require.Equal(t, []api.CodeFrequency{
+		{Time: 1659830400, Additions: 101, Deletions: -22},
+		{Time: 1659225600, Additions: 15, Deletions: -1},
+	}, frequencies)
+
+	require.Empty(t, api.WeeklyCodeFrequency(nil))
+}
@@ -0,0 +1,84 @@
+package gitea
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/kokoichi206/go-git-stats/api"
+	"github.com/kokoichi206/go-git-stats/api/internal/rest"
+	"github.com/kokoichi206/go-git-stats/util"
+)
+
+// Number of items per page (default maximum of Gitea, which may be lower on servers).
+const limit = 50
+
+// struct that implements ApiCaller with Gitea (and Forgejo) REST API v1.
+// For detailed information, see the official documentations:
+// https://docs.gitea.com/development/api-usage
+type Api struct {
+	config util.Config
+	client *http.Client
+}
+
+// Returns the ApiCaller of the Gitea REST API v1.
+// The options of the GitHub API (e.g. the user agent and the logger) apply to its requests.
+func New(config util.Config, opts ...api.Option) api.ApiCaller {
+	return &Api{
+		config: config,
+		client: api.NewHTTPClient(config, opts...),
+	}
+}
+
+// Base URL of the REST API.
+func (a *Api) baseURL() string {
+	return strings.TrimSuffix(a.config.Gitea.BaseURL, "/") + "/api/v1"
+}
+
+// Call GET method of the REST API and return the body and the header of the response.
+func (a *Api) get(URL string) ([]byte, http.Header, error) {
+
+	header := http.Header{}
+	header.Add("Accept", "application/json")
+	if a.config.Gitea.Token != "" {
+		header.Add("Authorization", fmt.Sprintf("token %s", a.config.Gitea.Token))
+	}
+
+	return rest.Get(a.client, URL, header)
+}
+
+// Call GET method for every page of the list and pass the body of each page to the callback,
+// which returns the number of items in the page.
+// Pages are read until the number of items reaches X-Total-Count, or an empty page without it.
+// Pages may have fewer items than the limit, since servers cap it (MAX_RESPONSE_ITEMS).
+// See documentation:
+// https://docs.gitea.com/development/api-usage#pagination
+func (a *Api) getAllPages(URL string, callback func(body []byte) (int, error)) error {
+
+	separator := "?"
+	if strings.Contains(URL, "?") {
+		separator = "&"
+	}
+
+	read := 0
+	for page := 1; ; page++ {
+		body, header, err := a.get(fmt.Sprintf("%s%spage=%d&limit=%d", URL, separator, page, limit))
+		if err != nil {
+			return err
+		}
+
+		count, err := callback(body)
+		if err != nil {
+			return err
+		}
+		read += count
+
+		if count == 0 {
+			return nil
+		}
+		if total, err := strconv.Atoi(header.Get("X-Total-Count")); err == nil && read >= total {
+			return nil
+		}
+	}
+}
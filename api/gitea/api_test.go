@@ -0,0 +1,256 @@
+package gitea_test
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/kokoichi206/go-git-stats/api"
+	"github.com/kokoichi206/go-git-stats/api/gitea"
+	"github.com/kokoichi206/go-git-stats/api/internal/resttest"
+	"github.com/kokoichi206/go-git-stats/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListRepositories(t *testing.T) {
+
+	ts := NewTestServer()
+	defer ts.Close()
+
+	config := util.Config{
+		Backend: util.BackendGitea,
+		Gitea: util.ProviderConfig{
+			BaseURL: ts.URL,
+			Token:   "kokoichi206token",
+		},
+	}
+	a := gitea.New(config)
+
+	testCases := []struct {
+		name      string
+		setup     func()
+		act       func() ([]api.Repository, error)
+		assertion func(t *testing.T, err error, repositories []api.Repository)
+	}{
+		{
+			name: "OK authenticated user with pagination",
+			setup: func() {
+				total := map[string]string{"X-Total-Count": "51"}
+				ts.Responses["/api/v1/user/repos"] = resttest.Response{StatusCode: http.StatusOK, Header: total, Body: mockRepositoriesFullPage()}
+				ts.Responses["/api/v1/user/repos?page=2"] = resttest.Response{StatusCode: http.StatusOK, Header: total, Body: mockRepositoriesLastPage}
+			},
+			act: a.ListRepositoriesForAuthenticatedUser,
+			assertion: func(t *testing.T, err error, repositories []api.Repository) {
+				require.NoError(t, err)
+				require.Equal(t, 51, len(repositories))
+				require.Equal(t, api.Repository{ID: 2, Private: true, Name: "repo-2", FullName: "kokoichi206/repo-2", Language: "Go"}, repositories[1])
+				require.Equal(t, api.Repository{ID: 51, Private: false, Name: "go-git-stats", FullName: "kokoichi206/go-git-stats", Language: "Go"}, repositories[50])
+
+				require.Equal(t, []string{
+					"/api/v1/user/repos?page=1&limit=50",
+					"/api/v1/user/repos?page=2&limit=50",
+				}, ts.Requests)
+				require.Equal(t, "token kokoichi206token", ts.RequestHeader.Get("Authorization"))
+			},
+		},
+		{
+			name: "OK short pages of the server without the total count",
+			setup: func() {
+				// MAX_RESPONSE_ITEMS of the server is lower than the limit.
+				ts.Responses["/api/v1/user/repos"] = resttest.Response{StatusCode: http.StatusOK, Body: mockRepositoriesLastPage}
+				ts.Responses["/api/v1/user/repos?page=2"] = resttest.Response{StatusCode: http.StatusOK, Body: mockRepositoriesLastPage}
+				ts.Responses["/api/v1/user/repos?page=3"] = resttest.Response{StatusCode: http.StatusOK, Body: `[]`}
+			},
+			act: a.ListRepositoriesForAuthenticatedUser,
+			assertion: func(t *testing.T, err error, repositories []api.Repository) {
+				require.NoError(t, err)
+				require.Equal(t, 2, len(repositories))
+				require.Equal(t, []string{
+					"/api/v1/user/repos?page=1&limit=50",
+					"/api/v1/user/repos?page=2&limit=50",
+					"/api/v1/user/repos?page=3&limit=50",
+				}, ts.Requests)
+			},
+		},
+		{
+			name: "OK user repositories",
+			setup: func() {
+				ts.Responses["/api/v1/users/kokoichi206/repos"] = resttest.Response{StatusCode: http.StatusOK, Header: map[string]string{"X-Total-Count": "1"}, Body: mockRepositoriesLastPage}
+			},
+			act: func() ([]api.Repository, error) {
+				return a.ListPublicRepositories("kokoichi206")
+			},
+			assertion: func(t *testing.T, err error, repositories []api.Repository) {
+				require.NoError(t, err)
+				require.Equal(t, 1, len(repositories))
+				require.Equal(t, []string{"/api/v1/users/kokoichi206/repos?page=1&limit=50"}, ts.Requests)
+			},
+		},
+		{
+			name: "OK organization repositories when the user is not found",
+			setup: func() {
+				ts.Responses["/api/v1/orgs/my-org/repos"] = resttest.Response{StatusCode: http.StatusOK, Header: map[string]string{"X-Total-Count": "1"}, Body: mockRepositoriesLastPage}
+			},
+			act: func() ([]api.Repository, error) {
+				return a.ListPublicRepositories("my-org")
+			},
+			assertion: func(t *testing.T, err error, repositories []api.Repository) {
+				require.NoError(t, err)
+				require.Equal(t, 1, len(repositories))
+				require.Equal(t, []string{
+					"/api/v1/users/my-org/repos?page=1&limit=50",
+					"/api/v1/orgs/my-org/repos?page=1&limit=50",
+				}, ts.Requests)
+			},
+		},
+		{
+			name: "Error Unauthorized",
+			setup: func() {
+				ts.Responses["/api/v1/user/repos"] = resttest.Response{StatusCode: http.StatusUnauthorized}
+			},
+			act: a.ListRepositoriesForAuthenticatedUser,
+			assertion: func(t *testing.T, err error, repositories []api.Repository) {
+				require.Error(t, err)
+				require.Equal(t, "failed to client.Do: StatusCode is 401", err.Error())
+				require.Nil(t, repositories)
+				require.Equal(t, 1, len(ts.Requests))
+			},
+		},
+		{
+			name: "Error after retry",
+			setup: func() {
+				ts.Responses["/api/v1/user/repos"] = resttest.Response{StatusCode: http.StatusBadGateway}
+			},
+			act: a.ListRepositoriesForAuthenticatedUser,
+			assertion: func(t *testing.T, err error, repositories []api.Repository) {
+				require.Error(t, err)
+				require.Equal(t, "failed to client.Do after several retries.", err.Error())
+				require.Equal(t, 3, len(ts.Requests))
+			},
+		},
+		{
+			name: "Unmarshal failed",
+			setup: func() {
+				ts.Responses["/api/v1/user/repos"] = resttest.Response{StatusCode: http.StatusOK, Body: `{"id": 1}`}
+			},
+			act: a.ListRepositoriesForAuthenticatedUser,
+			assertion: func(t *testing.T, err error, repositories []api.Repository) {
+				require.Error(t, err)
+				require.True(t, strings.Contains(err.Error(), "json.Unmarshal"))
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			tc.setup()
+			defer ts.Reset()
+
+			// Act
+			repositories, err := tc.act()
+
+			// Assert
+			tc.assertion(t, err, repositories)
+		})
+	}
+}
+
+func TestWeeklyCommitActivity(t *testing.T) {
+
+	ts := NewTestServer()
+	defer ts.Close()
+
+	a := gitea.New(util.Config{Gitea: util.ProviderConfig{BaseURL: ts.URL + "/"}})
+
+	testCases := []struct {
+		name      string
+		setup     func()
+		assertion func(t *testing.T, err error, frequencies []api.CodeFrequency)
+	}{
+		{
+			name: "OK",
+			setup: func() {
+				ts.Responses["/api/v1/repos/kokoichi206/go-git-stats/commits"] = resttest.Response{StatusCode: http.StatusOK, Header: map[string]string{"X-Total-Count": "3"}, Body: mockCommits}
+			},
+			assertion: func(t *testing.T, err error, frequencies []api.CodeFrequency) {
+				require.NoError(t, err)
+				// Merge commits are not counted
+				require.Equal(t, []api.CodeFrequency{
+					{Time: 1659830400, Additions: 15, Deletions: -4},
+					{Time: 1659225600, Additions: 100, Deletions: 0},
+				}, frequencies)
+				require.Equal(t, []string{
+					"/api/v1/repos/kokoichi206/go-git-stats/commits?stat=true&verification=false&files=false&page=1&limit=50",
+				}, ts.Requests)
+				// No token
+				require.Equal(t, "", ts.RequestHeader.Get("Authorization"))
+			},
+		},
+		{
+			name: "OK empty repository",
+			setup: func() {
+				ts.Responses["/api/v1/repos/kokoichi206/go-git-stats/commits"] = resttest.Response{StatusCode: http.StatusConflict}
+			},
+			assertion: func(t *testing.T, err error, frequencies []api.CodeFrequency) {
+				require.NoError(t, err)
+				require.Empty(t, frequencies)
+			},
+		},
+		{
+			name:  "Error Not Found",
+			setup: func() {},
+			assertion: func(t *testing.T, err error, frequencies []api.CodeFrequency) {
+				require.Error(t, err)
+				require.True(t, strings.Contains(err.Error(), "StatusCode is 404"))
+				require.Nil(t, frequencies)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			tc.setup()
+			defer ts.Reset()
+
+			// Act
+			frequencies, err := a.WeeklyCommitActivity("kokoichi206/go-git-stats")
+
+			// Assert
+			tc.assertion(t, err, frequencies)
+		})
+	}
+}
+
+func TestLanguages(t *testing.T) {
+
+	ts := NewTestServer()
+	defer ts.Close()
+
+	a := gitea.New(util.Config{Gitea: util.ProviderConfig{BaseURL: ts.URL}})
+	ts.Responses["/api/v1/repos/kokoichi206/go-git-stats/languages"] = resttest.Response{StatusCode: http.StatusOK, Body: mockLanguages}
+
+	// Act
+	languages, err := a.Languages("kokoichi206/go-git-stats")
+
+	// Assert
+	require.NoError(t, err)
+	require.Equal(t, map[string]int{"Go": 40500, "Shell": 1520}, languages)
+}
+
+func TestParticipation(t *testing.T) {
+
+	a := gitea.New(util.Config{})
+
+	// Act
+	_, err := a.Participation("kokoichi206/go-git-stats")
+
+	// Assert
+	require.True(t, errors.Is(err, api.ErrNotSupported))
+}
@@ -0,0 +1,114 @@
+package gitea_test
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Full page (50 repositories) of the user.
+func mockRepositoriesFullPage() string {
+	var repositories []map[string]interface{}
+	for i := 1; i <= 50; i++ {
+		repositories = append(repositories, map[string]interface{}{
+			"id":        i,
+			"name":      fmt.Sprintf("repo-%d", i),
+			"full_name": fmt.Sprintf("kokoichi206/repo-%d", i),
+			"private":   i%2 == 0,
+			"language":  "Go",
+		})
+	}
+	data, _ := json.Marshal(repositories)
+	return string(data)
+}
+
+// Last page (1 repository) of the user.
+const mockRepositoriesLastPage = `[
+  {
+    "id": 51,
+    "owner": {
+      "id": 1,
+      "login": "kokoichi206",
+      "full_name": ""
+    },
+    "name": "go-git-stats",
+    "full_name": "kokoichi206/go-git-stats",
+    "description": "",
+    "empty": false,
+    "private": false,
+    "fork": false,
+    "language": "Go",
+    "html_url": "https://gitea.example.com/kokoichi206/go-git-stats",
+    "default_branch": "main"
+  }
+]`
+
+// 3 commits including a merge commit
+const mockCommits = `[
+  {
+    "url": "https://gitea.example.com/api/v1/repos/kokoichi206/go-git-stats/git/commits/c3",
+    "sha": "c3",
+    "created": "2022-08-10T09:00:00+09:00",
+    "commit": {
+      "author": {
+        "name": "kokoichi206",
+        "email": "kokoichi206@example.com",
+        "date": "2022-08-10T09:00:00+09:00"
+      },
+      "message": "Merge branch 'feature'"
+    },
+    "parents": [
+      {"sha": "c2"},
+      {"sha": "c1"}
+    ],
+    "stats": {
+      "total": 2000,
+      "additions": 1000,
+      "deletions": 1000
+    }
+  },
+  {
+    "url": "https://gitea.example.com/api/v1/repos/kokoichi206/go-git-stats/git/commits/c2",
+    "sha": "c2",
+    "created": "2022-08-10T09:00:00+09:00",
+    "commit": {
+      "author": {
+        "name": "kokoichi206",
+        "email": "kokoichi206@example.com",
+        "date": "2022-08-10T09:00:00+09:00"
+      },
+      "message": "Add feature"
+    },
+    "parents": [
+      {"sha": "c1"}
+    ],
+    "stats": {
+      "total": 19,
+      "additions": 15,
+      "deletions": 4
+    }
+  },
+  {
+    "url": "https://gitea.example.com/api/v1/repos/kokoichi206/go-git-stats/git/commits/c1",
+    "sha": "c1",
+    "created": "2022-08-03T12:00:00Z",
+    "commit": {
+      "author": {
+        "name": "kokoichi206",
+        "email": "kokoichi206@example.com",
+        "date": "2022-08-03T12:00:00Z"
+      },
+      "message": "Initial commit"
+    },
+    "parents": [],
+    "stats": {
+      "total": 100,
+      "additions": 100,
+      "deletions": 0
+    }
+  }
+]`
+
+const mockLanguages = `{
+  "Go": 40500,
+  "Shell": 1520
+}`
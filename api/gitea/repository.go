@@ -0,0 +1,72 @@
+package gitea
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/kokoichi206/go-git-stats/api"
+	"github.com/kokoichi206/go-git-stats/api/internal/rest"
+)
+
+// Lists repositories of a user, or of an organization if the user is not found.
+// See documentation:
+// https://docs.gitea.com/api/1.20/#tag/user/operation/userListRepos
+// https://docs.gitea.com/api/1.20/#tag/organization/operation/orgListRepos
+func (a *Api) ListPublicRepositories(userName string) ([]api.Repository, error) {
+
+	repositories, err := a.listRepositories(fmt.Sprintf("%s/users/%s/repos", a.baseURL(), url.PathEscape(userName)))
+	if rest.IsStatus(err, http.StatusNotFound) {
+		return a.listRepositories(fmt.Sprintf("%s/orgs/%s/repos", a.baseURL(), url.PathEscape(userName)))
+	}
+
+	return repositories, err
+}
+
+// Lists repositories for the authenticated user.
+// Config must have the Gitea access token.
+// See documentation:
+// https://docs.gitea.com/api/1.20/#tag/user/operation/userCurrentListRepos
+func (a *Api) ListRepositoriesForAuthenticatedUser() ([]api.Repository, error) {
+	return a.listRepositories(fmt.Sprintf("%s/user/repos", a.baseURL()))
+}
+
+// Lists languages for the specified repository.
+// The value shown for each language is the number of bytes like GitHub.
+// See documentation:
+// https://docs.gitea.com/api/1.20/#tag/repository/operation/repoGetLanguages
+func (a *Api) Languages(fullName string) (map[string]int, error) {
+
+	body, _, err := a.get(fmt.Sprintf("%s/repos/%s/languages", a.baseURL(), fullName))
+	if err != nil {
+		return nil, err
+	}
+
+	var languages map[string]int
+	if err := json.Unmarshal(body, &languages); err != nil {
+		return nil, fmt.Errorf("failed to json.Unmarshal: %w", err)
+	}
+
+	return languages, nil
+}
+
+// List all repositories of the URL.
+// Repositories of Gitea have the same fields as GitHub.
+func (a *Api) listRepositories(URL string) ([]api.Repository, error) {
+
+	var repositories []api.Repository
+	err := a.getAllPages(URL, func(body []byte) (int, error) {
+		var page []api.Repository
+		if err := json.Unmarshal(body, &page); err != nil {
+			return 0, fmt.Errorf("failed to json.Unmarshal: %w", err)
+		}
+		repositories = append(repositories, page...)
+		return len(page), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return repositories, nil
+}
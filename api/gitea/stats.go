@@ -0,0 +1,71 @@
+package gitea
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/kokoichi206/go-git-stats/api"
+	"github.com/kokoichi206/go-git-stats/api/internal/rest"
+)
+
+// Commit of Gitea with stats.
+type commit struct {
+	Commit struct {
+		Author struct {
+			Date time.Time `json:"date"`
+		} `json:"author"`
+	} `json:"commit"`
+	Parents []struct {
+		SHA string `json:"sha"`
+	} `json:"parents"`
+	Stats struct {
+		Additions int `json:"additions"`
+		Deletions int `json:"deletions"`
+	} `json:"stats"`
+}
+
+// Get the weekly commit activity of the repository.
+// Gitea has no statistics like GitHub,
+// so the line changes of all commits (except merges) of the default branch are aggregated.
+// Gitea responds 409 Conflict for an empty repository, which has no commits.
+// See documentation:
+// https://docs.gitea.com/api/1.20/#tag/repository/operation/repoGetAllCommits
+func (a *Api) WeeklyCommitActivity(fullName string) ([]api.CodeFrequency, error) {
+
+	// Changed files are not needed.
+	URL := fmt.Sprintf("%s/repos/%s/commits?stat=true&verification=false&files=false", a.baseURL(), fullName)
+
+	var stats []api.CommitStat
+	err := a.getAllPages(URL, func(body []byte) (int, error) {
+		var commits []commit
+		if err := json.Unmarshal(body, &commits); err != nil {
+			return 0, fmt.Errorf("failed to json.Unmarshal: %w", err)
+		}
+		for _, c := range commits {
+			if len(c.Parents) > 1 {
+				continue
+			}
+			stats = append(stats, api.CommitStat{
+				Time:      c.Commit.Author.Date,
+				Additions: c.Stats.Additions,
+				Deletions: c.Stats.Deletions,
+			})
+		}
+		return len(commits), nil
+	})
+	if rest.IsStatus(err, http.StatusConflict) {
+		return api.WeeklyCodeFrequency(nil), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return api.WeeklyCodeFrequency(stats), nil
+}
+
+// Gitea has no owner of commits like GitHub statistics.
+func (a *Api) Participation(fullName string) (api.Participation, error) {
+	return api.Participation{}, fmt.Errorf("participation of Gitea: %w", api.ErrNotSupported)
+}
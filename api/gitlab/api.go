@@ -0,0 +1,76 @@
+package gitlab
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/kokoichi206/go-git-stats/api"
+	"github.com/kokoichi206/go-git-stats/api/internal/rest"
+	"github.com/kokoichi206/go-git-stats/util"
+)
+
+// Number of items per page (maximum of GitLab).
+const perPage = 100
+
+// struct that implements ApiCaller with GitLab REST API v4.
+// FullName of each repository is the path with namespace of the project.
+// For detailed information, see the official documentations:
+// https://docs.gitlab.com/ee/api/
+type Api struct {
+	config util.Config
+	client *http.Client
+}
+
+// Returns the ApiCaller of the GitLab REST API v4.
+// The options of the GitHub API (e.g. the user agent and the logger) apply to its requests.
+func New(config util.Config, opts ...api.Option) api.ApiCaller {
+	return &Api{
+		config: config,
+		client: api.NewHTTPClient(config, opts...),
+	}
+}
+
+// Base URL of the REST API.
+func (a *Api) baseURL() string {
+	return strings.TrimSuffix(a.config.GitLab.BaseURL, "/") + "/api/v4"
+}
+
+// Call GET method of the REST API and return the body and the header of the response.
+func (a *Api) get(URL string) ([]byte, http.Header, error) {
+
+	header := http.Header{}
+	header.Add("Accept", "application/json")
+	if a.config.GitLab.Token != "" {
+		header.Add("PRIVATE-TOKEN", a.config.GitLab.Token)
+	}
+
+	return rest.Get(a.client, URL, header)
+}
+
+// Call GET method for every page of the list and pass the body of each page to the callback.
+// See documentation:
+// https://docs.gitlab.com/ee/api/rest/#pagination
+func (a *Api) getAllPages(URL string, callback func(body []byte) error) error {
+
+	page := "1"
+	for page != "" {
+		separator := "?"
+		if strings.Contains(URL, "?") {
+			separator = "&"
+		}
+
+		body, header, err := a.get(fmt.Sprintf("%s%sper_page=%d&page=%s", URL, separator, perPage, page))
+		if err != nil {
+			return err
+		}
+		if err := callback(body); err != nil {
+			return err
+		}
+
+		// Empty at the last page.
+		page = header.Get("X-Next-Page")
+	}
+
+	return nil
+}
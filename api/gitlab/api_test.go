@@ -0,0 +1,273 @@
+package gitlab_test
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/kokoichi206/go-git-stats/api"
+	"github.com/kokoichi206/go-git-stats/api/gitlab"
+	"github.com/kokoichi206/go-git-stats/api/internal/resttest"
+	"github.com/kokoichi206/go-git-stats/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListRepositories(t *testing.T) {
+
+	ts := NewTestServer()
+	defer ts.Close()
+
+	config := util.Config{
+		Backend: util.BackendGitLab,
+		GitLab: util.ProviderConfig{
+			BaseURL: ts.URL + "/",
+			Token:   "glpat-kokoichi206token",
+		},
+	}
+	a := gitlab.New(config)
+
+	testCases := []struct {
+		name      string
+		setup     func()
+		act       func() ([]api.Repository, error)
+		assertion func(t *testing.T, err error, repositories []api.Repository)
+	}{
+		{
+			name: "OK authenticated user with pagination",
+			setup: func() {
+				ts.Responses["/api/v4/projects"] = resttest.Response{StatusCode: http.StatusOK, Header: map[string]string{"X-Next-Page": "2"}, Body: mockProjectsPage1}
+				ts.Responses["/api/v4/projects?page=2"] = resttest.Response{StatusCode: http.StatusOK, Body: mockProjectsPage2}
+			},
+			act: a.ListRepositoriesForAuthenticatedUser,
+			assertion: func(t *testing.T, err error, repositories []api.Repository) {
+				require.NoError(t, err)
+				require.Equal(t, []api.Repository{
+					{ID: 4, Private: false, Name: "Diaspora Client", FullName: "diaspora/diaspora-client"},
+					{ID: 6, Private: true, Name: "Puppet", FullName: "brightbox/puppet"},
+					{ID: 8, Private: true, Name: "Tools", FullName: "diaspora/sub/tools"},
+				}, repositories)
+
+				require.Equal(t, []string{
+					"/api/v4/projects?membership=true&per_page=100&page=1",
+					"/api/v4/projects?membership=true&per_page=100&page=2",
+				}, ts.Requests)
+				require.Equal(t, "glpat-kokoichi206token", ts.RequestHeader.Get("PRIVATE-TOKEN"))
+			},
+		},
+		{
+			name: "OK user projects",
+			setup: func() {
+				ts.Responses["/api/v4/users/diaspora/projects"] = resttest.Response{StatusCode: http.StatusOK, Body: mockProjectsPage2}
+			},
+			act: func() ([]api.Repository, error) {
+				return a.ListPublicRepositories("diaspora")
+			},
+			assertion: func(t *testing.T, err error, repositories []api.Repository) {
+				require.NoError(t, err)
+				require.Equal(t, 1, len(repositories))
+				require.Equal(t, 1, len(ts.Requests))
+			},
+		},
+		{
+			name: "OK group projects when the user is not found",
+			setup: func() {
+				ts.Responses["/api/v4/groups/diaspora/projects"] = resttest.Response{StatusCode: http.StatusOK, Body: mockProjectsPage1}
+			},
+			act: func() ([]api.Repository, error) {
+				return a.ListPublicRepositories("diaspora")
+			},
+			assertion: func(t *testing.T, err error, repositories []api.Repository) {
+				require.NoError(t, err)
+				require.Equal(t, 2, len(repositories))
+				require.Equal(t, []string{
+					"/api/v4/users/diaspora/projects?per_page=100&page=1",
+					"/api/v4/groups/diaspora/projects?include_subgroups=true&per_page=100&page=1",
+				}, ts.Requests)
+			},
+		},
+		{
+			name:  "Error neither user nor group",
+			setup: func() {},
+			act: func() ([]api.Repository, error) {
+				return a.ListPublicRepositories("nobody")
+			},
+			assertion: func(t *testing.T, err error, repositories []api.Repository) {
+				require.Error(t, err)
+				require.Equal(t, "failed to client.Do: StatusCode is 404: 404 Not found", err.Error())
+				require.True(t, errors.Is(err, api.ErrNotFound))
+				require.Nil(t, repositories)
+			},
+		},
+		{
+			name: "Error after retry",
+			setup: func() {
+				ts.Responses["/api/v4/projects"] = resttest.Response{StatusCode: http.StatusInternalServerError}
+			},
+			act: a.ListRepositoriesForAuthenticatedUser,
+			assertion: func(t *testing.T, err error, repositories []api.Repository) {
+				require.Error(t, err)
+				require.Equal(t, "failed to client.Do after several retries.", err.Error())
+				require.Equal(t, 3, len(ts.Requests))
+			},
+		},
+		{
+			name: "Unmarshal failed",
+			setup: func() {
+				ts.Responses["/api/v4/projects"] = resttest.Response{StatusCode: http.StatusOK, Body: `{"id": 1}`}
+			},
+			act: a.ListRepositoriesForAuthenticatedUser,
+			assertion: func(t *testing.T, err error, repositories []api.Repository) {
+				require.Error(t, err)
+				require.True(t, strings.Contains(err.Error(), "json.Unmarshal"))
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			tc.setup()
+			defer ts.Reset()
+
+			// Act
+			repositories, err := tc.act()
+
+			// Assert
+			tc.assertion(t, err, repositories)
+		})
+	}
+}
+
+func TestWeeklyCommitActivity(t *testing.T) {
+
+	ts := NewTestServer()
+	defer ts.Close()
+
+	a := gitlab.New(util.Config{GitLab: util.ProviderConfig{BaseURL: ts.URL}})
+
+	testCases := []struct {
+		name      string
+		setup     func()
+		assertion func(t *testing.T, err error, frequencies []api.CodeFrequency)
+	}{
+		{
+			name: "OK",
+			setup: func() {
+				ts.Responses["/api/v4/projects/diaspora%2Fsub%2Ftools/repository/commits"] = resttest.Response{StatusCode: http.StatusOK, Header: map[string]string{"X-Next-Page": "2"}, Body: mockCommits}
+				ts.Responses["/api/v4/projects/diaspora%2Fsub%2Ftools/repository/commits?page=2"] = resttest.Response{StatusCode: http.StatusOK, Body: `[]`}
+			},
+			assertion: func(t *testing.T, err error, frequencies []api.CodeFrequency) {
+				require.NoError(t, err)
+				// Merge commits are not counted
+				require.Equal(t, []api.CodeFrequency{
+					{Time: 1659830400, Additions: 15, Deletions: -4},
+					{Time: 1659225600, Additions: 100, Deletions: 0},
+				}, frequencies)
+				require.Equal(t, 2, len(ts.Requests))
+				require.True(t, strings.Contains(ts.Requests[0], "with_stats=true"))
+				// No token
+				require.Equal(t, "", ts.RequestHeader.Get("PRIVATE-TOKEN"))
+			},
+		},
+		{
+			name:  "Error Not Found",
+			setup: func() {},
+			assertion: func(t *testing.T, err error, frequencies []api.CodeFrequency) {
+				require.Error(t, err)
+				require.True(t, strings.Contains(err.Error(), "StatusCode is 404"))
+				require.Nil(t, frequencies)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			tc.setup()
+			defer ts.Reset()
+
+			// Act
+			frequencies, err := a.WeeklyCommitActivity("diaspora/sub/tools")
+
+			// Assert
+			tc.assertion(t, err, frequencies)
+		})
+	}
+}
+
+func TestLanguages(t *testing.T) {
+
+	ts := NewTestServer()
+	defer ts.Close()
+
+	testCases := []struct {
+		name      string
+		languages string
+		project   string
+		assertion func(t *testing.T, err error, languages map[string]int)
+	}{
+		{
+			name:      "OK",
+			languages: mockLanguages,
+			project:   mockProjectStatistics,
+			assertion: func(t *testing.T, err error, languages map[string]int) {
+				require.NoError(t, err)
+				// 80.5% and 19.5% of 1038090 bytes
+				require.Equal(t, map[string]int{"Go": 835662, "Shell": 202428}, languages)
+			},
+		},
+		{
+			name:      "OK without languages",
+			languages: `{}`,
+			assertion: func(t *testing.T, err error, languages map[string]int) {
+				require.NoError(t, err)
+				require.Empty(t, languages)
+			},
+		},
+		{
+			name:      "Error without statistics",
+			languages: mockLanguages,
+			project:   mockProjectWithoutStatistics,
+			assertion: func(t *testing.T, err error, languages map[string]int) {
+				require.EqualError(t, err, "failed to read the statistics of diaspora/diaspora-client, which need at least the Reporter role")
+				require.Nil(t, languages)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			ts.Reset()
+			a := gitlab.New(util.Config{GitLab: util.ProviderConfig{BaseURL: ts.URL}})
+			ts.Responses["/api/v4/projects/diaspora%2Fdiaspora-client/languages"] = resttest.Response{StatusCode: http.StatusOK, Body: tc.languages}
+			if tc.project != "" {
+				ts.Responses["/api/v4/projects/diaspora%2Fdiaspora-client"] = resttest.Response{StatusCode: http.StatusOK, Body: tc.project}
+			}
+
+			// Act
+			languages, err := a.Languages("diaspora/diaspora-client")
+
+			// Assert
+			tc.assertion(t, err, languages)
+		})
+	}
+}
+
+func TestParticipation(t *testing.T) {
+
+	a := gitlab.New(util.Config{})
+
+	// Act
+	_, err := a.Participation("diaspora/diaspora-client")
+
+	// Assert
+	require.True(t, errors.Is(err, api.ErrNotSupported))
+}
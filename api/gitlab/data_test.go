@@ -0,0 +1,114 @@
+package gitlab_test
+
+// 2 projects (1st page)
+const mockProjectsPage1 = `[
+  {
+    "id": 4,
+    "description": null,
+    "name": "Diaspora Client",
+    "name_with_namespace": "Diaspora / Diaspora Client",
+    "path": "diaspora-client",
+    "path_with_namespace": "diaspora/diaspora-client",
+    "visibility": "public",
+    "default_branch": "main"
+  },
+  {
+    "id": 6,
+    "description": null,
+    "name": "Puppet",
+    "name_with_namespace": "Brightbox / Puppet",
+    "path": "puppet",
+    "path_with_namespace": "brightbox/puppet",
+    "visibility": "private",
+    "default_branch": "main"
+  }
+]`
+
+// 1 project (2nd page)
+const mockProjectsPage2 = `[
+  {
+    "id": 8,
+    "description": null,
+    "name": "Tools",
+    "name_with_namespace": "Diaspora / Sub / Tools",
+    "path": "tools",
+    "path_with_namespace": "diaspora/sub/tools",
+    "visibility": "internal",
+    "default_branch": "main"
+  }
+]`
+
+// 3 commits including a merge commit
+const mockCommits = `[
+  {
+    "id": "6104942438c14ec7bd21c6cd5bd995272b3faff6",
+    "short_id": "6104942438c",
+    "title": "Merge branch 'feature'",
+    "author_name": "randx",
+    "author_email": "user@example.com",
+    "authored_date": "2022-08-10T09:00:00.000+09:00",
+    "parent_ids": [
+      "ae1d9fb46aa2b07ee9836d49862ec4e2c46fbbba",
+      "7b5c3cc8be40ee161ae89a06bba6229da1032a0c"
+    ],
+    "stats": {
+      "additions": 1000,
+      "deletions": 1000,
+      "total": 2000
+    }
+  },
+  {
+    "id": "ae1d9fb46aa2b07ee9836d49862ec4e2c46fbbba",
+    "short_id": "ae1d9fb46aa",
+    "title": "Add feature",
+    "author_name": "randx",
+    "author_email": "user@example.com",
+    "authored_date": "2022-08-10T09:00:00.000+09:00",
+    "parent_ids": [
+      "7b5c3cc8be40ee161ae89a06bba6229da1032a0c"
+    ],
+    "stats": {
+      "additions": 15,
+      "deletions": 4,
+      "total": 19
+    }
+  },
+  {
+    "id": "7b5c3cc8be40ee161ae89a06bba6229da1032a0c",
+    "short_id": "7b5c3cc8be4",
+    "title": "Initial commit",
+    "author_name": "randx",
+    "author_email": "user@example.com",
+    "authored_date": "2022-08-03T12:00:00.000Z",
+    "parent_ids": [],
+    "stats": {
+      "additions": 100,
+      "deletions": 0,
+      "total": 100
+    }
+  }
+]`
+
+const mockLanguages = `{
+  "Go": 80.5,
+  "Shell": 19.5
+}`
+
+const mockProjectStatistics = `{
+  "id": 4,
+  "name": "Diaspora Client",
+  "path_with_namespace": "diaspora/diaspora-client",
+  "visibility": "public",
+  "statistics": {
+    "commit_count": 37,
+    "storage_size": 1038090,
+    "repository_size": 1038090
+  }
+}`
+
+const mockProjectWithoutStatistics = `{
+  "id": 4,
+  "name": "Diaspora Client",
+  "path_with_namespace": "diaspora/diaspora-client",
+  "visibility": "public"
+}`
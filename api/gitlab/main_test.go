@@ -0,0 +1,17 @@
+package gitlab_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/kokoichi206/go-git-stats/api/internal/resttest"
+)
+
+// Stand-in server of GitLab which responds by the escaped path and page.
+func NewTestServer() *resttest.Server {
+	return resttest.NewServer(`{"message":"404 Not found"}`, resttest.Page{Param: "page", First: "1"})
+}
+
+func TestMain(m *testing.M) {
+	os.Exit(m.Run())
+}
@@ -0,0 +1,117 @@
+package gitlab
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/kokoichi206/go-git-stats/api"
+	"github.com/kokoichi206/go-git-stats/api/internal/rest"
+)
+
+// Project of GitLab.
+type project struct {
+	ID                int    `json:"id"`
+	Name              string `json:"name"`
+	PathWithNamespace string `json:"path_with_namespace"`
+	Visibility        string `json:"visibility"`
+	// Only with statistics=true, and for members of the Reporter role or above.
+	Statistics *struct {
+		RepositorySize int64 `json:"repository_size"`
+	} `json:"statistics"`
+}
+
+// Lists projects of a user, or of a group (including subgroups) if the user is not found.
+// See documentation:
+// https://docs.gitlab.com/ee/api/projects.html#list-user-projects
+// https://docs.gitlab.com/ee/api/groups.html#list-a-groups-projects
+func (a *Api) ListPublicRepositories(userName string) ([]api.Repository, error) {
+
+	URL := fmt.Sprintf("%s/users/%s/projects", a.baseURL(), url.PathEscape(userName))
+	repositories, err := a.listProjects(URL)
+
+	if rest.IsStatus(err, http.StatusNotFound) {
+		URL = fmt.Sprintf("%s/groups/%s/projects?include_subgroups=true", a.baseURL(), url.PathEscape(userName))
+		return a.listProjects(URL)
+	}
+
+	return repositories, err
+}
+
+// Lists projects which the authenticated user is a member of.
+// Config must have the GitLab access token.
+// See documentation:
+// https://docs.gitlab.com/ee/api/projects.html#list-all-projects
+func (a *Api) ListRepositoriesForAuthenticatedUser() ([]api.Repository, error) {
+	return a.listProjects(fmt.Sprintf("%s/projects?membership=true", a.baseURL()))
+}
+
+// Lists languages of the project in bytes.
+// GitLab returns percentages of languages,
+// so they are converted to bytes with the repository size in the statistics of the project,
+// which needs at least the Reporter role.
+// See documentation:
+// https://docs.gitlab.com/ee/api/projects.html#languages
+// https://docs.gitlab.com/ee/api/projects.html#get-single-project
+func (a *Api) Languages(fullName string) (map[string]int, error) {
+
+	body, _, err := a.get(fmt.Sprintf("%s/projects/%s/languages", a.baseURL(), url.PathEscape(fullName)))
+	if err != nil {
+		return nil, err
+	}
+
+	var percentages map[string]float64
+	if err := json.Unmarshal(body, &percentages); err != nil {
+		return nil, fmt.Errorf("failed to json.Unmarshal: %w", err)
+	}
+	if len(percentages) == 0 {
+		return map[string]int{}, nil
+	}
+
+	body, _, err = a.get(fmt.Sprintf("%s/projects/%s?statistics=true", a.baseURL(), url.PathEscape(fullName)))
+	if err != nil {
+		return nil, err
+	}
+
+	var p project
+	if err := json.Unmarshal(body, &p); err != nil {
+		return nil, fmt.Errorf("failed to json.Unmarshal: %w", err)
+	}
+	if p.Statistics == nil {
+		return nil, fmt.Errorf("failed to read the statistics of %s, which need at least the Reporter role", fullName)
+	}
+
+	languages := make(map[string]int, len(percentages))
+	for name, percentage := range percentages {
+		languages[name] = int(percentage/100*float64(p.Statistics.RepositorySize) + 0.5)
+	}
+
+	return languages, nil
+}
+
+// List all projects of the URL.
+func (a *Api) listProjects(URL string) ([]api.Repository, error) {
+
+	var repositories []api.Repository
+	err := a.getAllPages(URL, func(body []byte) error {
+		var projects []project
+		if err := json.Unmarshal(body, &projects); err != nil {
+			return fmt.Errorf("failed to json.Unmarshal: %w", err)
+		}
+		for _, p := range projects {
+			repositories = append(repositories, api.Repository{
+				ID:       p.ID,
+				Private:  p.Visibility != "public",
+				Name:     p.Name,
+				FullName: p.PathWithNamespace,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return repositories, nil
+}
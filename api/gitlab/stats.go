@@ -0,0 +1,59 @@
+package gitlab
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/kokoichi206/go-git-stats/api"
+)
+
+// Commit of GitLab with stats.
+type commit struct {
+	AuthoredDate time.Time `json:"authored_date"`
+	ParentIDs    []string  `json:"parent_ids"`
+	Stats        struct {
+		Additions int `json:"additions"`
+		Deletions int `json:"deletions"`
+	} `json:"stats"`
+}
+
+// Get the weekly commit activity of the project.
+// GitLab has no statistics like GitHub,
+// so the line changes of all commits (except merges) of the default branch are aggregated.
+// See documentation:
+// https://docs.gitlab.com/ee/api/commits.html#list-repository-commits
+func (a *Api) WeeklyCommitActivity(fullName string) ([]api.CodeFrequency, error) {
+
+	URL := fmt.Sprintf("%s/projects/%s/repository/commits?with_stats=true", a.baseURL(), url.PathEscape(fullName))
+
+	var stats []api.CommitStat
+	err := a.getAllPages(URL, func(body []byte) error {
+		var commits []commit
+		if err := json.Unmarshal(body, &commits); err != nil {
+			return fmt.Errorf("failed to json.Unmarshal: %w", err)
+		}
+		for _, c := range commits {
+			if len(c.ParentIDs) > 1 {
+				continue
+			}
+			stats = append(stats, api.CommitStat{
+				Time:      c.AuthoredDate,
+				Additions: c.Stats.Additions,
+				Deletions: c.Stats.Deletions,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return api.WeeklyCodeFrequency(stats), nil
+}
+
+// GitLab has no owner of commits like GitHub statistics.
+func (a *Api) Participation(fullName string) (api.Participation, error) {
+	return api.Participation{}, fmt.Errorf("participation of GitLab: %w", api.ErrNotSupported)
+}
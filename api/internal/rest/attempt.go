@@ -0,0 +1,38 @@
+package rest
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+)
+
+// Key of the counter of attempts in the context of the request.
+type attemptsKey struct{}
+
+// Returns the request whose attempts are counted while it is retried.
+func NewRequest(method, URL string) (*http.Request, error) {
+
+	req, err := http.NewRequest(method, URL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req.WithContext(context.WithValue(req.Context(), attemptsKey{}, new(int32))), nil
+}
+
+// Count an attempt of the request, and returns the number of attempts including it.
+// Requests not made by NewRequest are not retried, so it is always 1.
+func CountAttempt(req *http.Request) int {
+	if attempts, ok := req.Context().Value(attemptsKey{}).(*int32); ok {
+		return int(atomic.AddInt32(attempts, 1))
+	}
+	return 1
+}
+
+// Returns the number of attempts of the request counted so far (1 for requests not made by NewRequest).
+func Attempt(req *http.Request) int {
+	if attempts, ok := req.Context().Value(attemptsKey{}).(*int32); ok {
+		return int(atomic.LoadInt32(attempts))
+	}
+	return 1
+}
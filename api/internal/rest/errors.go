@@ -0,0 +1,143 @@
+package rest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Errors which StatusError matches with errors.Is.
+var (
+	ErrNotFound     = errors.New("not found")
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrForbidden    = errors.New("forbidden")
+	ErrRateLimited  = errors.New("rate limit exceeded")
+	ErrStatsPending = errors.New("statistics are being computed")
+)
+
+// Size limit of the body read for the error message.
+const maxErrorBody = 64 * 1024
+
+// Error of the response with unexpected status code.
+type StatusError struct {
+	StatusCode int
+	// URL of the request.
+	URL string
+	// Error message of the body (e.g. "Bad credentials"), empty if unknown.
+	Message string
+}
+
+func (e *StatusError) Error() string {
+
+	msg := fmt.Sprintf("failed to client.Do: StatusCode is %d", e.StatusCode)
+	if e.Message != "" {
+		msg += ": " + e.Message
+	}
+	return msg
+}
+
+func (e *StatusError) Is(target error) bool {
+
+	switch e.StatusCode {
+	case http.StatusUnauthorized:
+		return target == ErrUnauthorized
+	case http.StatusForbidden:
+		return target == ErrForbidden
+	case http.StatusNotFound:
+		return target == ErrNotFound
+	case http.StatusTooManyRequests:
+		return target == ErrRateLimited
+	case http.StatusAccepted:
+		// Statistics of GitHub are not cached yet.
+		return target == ErrStatsPending
+	}
+	return false
+}
+
+// Error of the response whose rate limit is exceeded.
+// It matches ErrRateLimited but not ErrForbidden even if the status code is 403.
+type RateLimitError struct {
+	StatusError
+	// When requests are allowed again, zero if unknown.
+	Reset time.Time
+}
+
+func (e *RateLimitError) Is(target error) bool {
+	return target == ErrRateLimited
+}
+
+// Returns the error of the response, whose body is read but not closed.
+func NewStatusError(resp *http.Response) error {
+
+	se := StatusError{StatusCode: resp.StatusCode}
+	if resp.Request != nil {
+		se.URL = resp.Request.URL.String()
+	}
+	if body, err := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody)); err == nil {
+		se.Message = errorMessage(body)
+	}
+
+	if reset, ok := rateLimitReset(resp); ok {
+		return &RateLimitError{StatusError: se, Reset: reset}
+	}
+	return &se
+}
+
+// Returns the error message of the body of GitHub, GitLab, Gitea or Bitbucket.
+func errorMessage(body []byte) string {
+
+	var message struct {
+		Message string `json:"message"`
+		Error   struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(body, &message); err != nil {
+		return ""
+	}
+
+	if message.Message != "" {
+		return strings.TrimSpace(message.Message)
+	}
+	return strings.TrimSpace(message.Error.Message)
+}
+
+// Returns when requests are allowed again, and whether the rate limit is exceeded.
+// The primary rate limit of GitHub has no remaining requests,
+// and the secondary one has the Retry-After header.
+// See documentation:
+// https://docs.github.com/en/rest/overview/resources-in-the-rest-api#exceeding-the-rate-limit
+func rateLimitReset(resp *http.Response) (time.Time, bool) {
+
+	if resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusTooManyRequests {
+		return time.Time{}, false
+	}
+
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+		return time.Now().Add(time.Duration(seconds) * time.Second), true
+	}
+
+	if resp.Header.Get("X-RateLimit-Remaining") != "0" {
+		return time.Time{}, resp.StatusCode == http.StatusTooManyRequests
+	}
+	if reset, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+		return time.Unix(reset, 0), true
+	}
+	return time.Time{}, true
+}
+
+// Returns whether the error is the response with the status code.
+func IsStatus(err error, statusCode int) bool {
+
+	var se *StatusError
+	if errors.As(err, &se) {
+		return se.StatusCode == statusCode
+	}
+	var re *RateLimitError
+	return errors.As(err, &re) && re.StatusCode == statusCode
+}
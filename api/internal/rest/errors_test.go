@@ -0,0 +1,171 @@
+package rest_test
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kokoichi206/go-git-stats/api/internal/rest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewStatusError(t *testing.T) {
+
+	testCases := []struct {
+		name   string
+		status int
+		header map[string]string
+		body   string
+		is     error
+		isNot  []error
+		// Expected Error() of the error.
+		expected string
+		// Whether the error is RateLimitError.
+		rateLimited bool
+		// Expected reset time of the rate limit (Unix time), zero means unknown.
+		reset int64
+	}{
+		{
+			name:     "Message of GitHub, GitLab and Gitea",
+			status:   http.StatusNotFound,
+			body:     `{"message": " Not Found ", "documentation_url": "https://docs.github.com/rest"}`,
+			is:       rest.ErrNotFound,
+			isNot:    []error{rest.ErrUnauthorized, rest.ErrForbidden, rest.ErrRateLimited, rest.ErrStatsPending},
+			expected: "failed to client.Do: StatusCode is 404: Not Found",
+		},
+		{
+			name:     "Message of Bitbucket",
+			status:   http.StatusUnauthorized,
+			body:     `{"type": "error", "error": {"message": "Access token expired."}}`,
+			is:       rest.ErrUnauthorized,
+			isNot:    []error{rest.ErrForbidden},
+			expected: "failed to client.Do: StatusCode is 401: Access token expired.",
+		},
+		{
+			name:     "Body which is not JSON",
+			status:   http.StatusForbidden,
+			body:     `<html>Forbidden</html>`,
+			is:       rest.ErrForbidden,
+			isNot:    []error{rest.ErrRateLimited},
+			expected: "failed to client.Do: StatusCode is 403",
+		},
+		{
+			name:     "Forbidden with remaining requests",
+			status:   http.StatusForbidden,
+			header:   map[string]string{"X-RateLimit-Remaining": "4999", "X-RateLimit-Reset": "1660136400"},
+			is:       rest.ErrForbidden,
+			isNot:    []error{rest.ErrRateLimited},
+			expected: "failed to client.Do: StatusCode is 403",
+		},
+		{
+			name:        "Primary rate limit",
+			status:      http.StatusForbidden,
+			header:      map[string]string{"X-RateLimit-Remaining": "0", "X-RateLimit-Reset": "1660136400"},
+			body:        `{"message": "API rate limit exceeded"}`,
+			is:          rest.ErrRateLimited,
+			isNot:       []error{rest.ErrForbidden},
+			expected:    "failed to client.Do: StatusCode is 403: API rate limit exceeded",
+			rateLimited: true,
+			reset:       1660136400,
+		},
+		{
+			name:        "Secondary rate limit",
+			status:      http.StatusForbidden,
+			header:      map[string]string{"Retry-After": "60"},
+			is:          rest.ErrRateLimited,
+			isNot:       []error{rest.ErrForbidden},
+			expected:    "failed to client.Do: StatusCode is 403",
+			rateLimited: true,
+		},
+		{
+			name:        "Too many requests without headers",
+			status:      http.StatusTooManyRequests,
+			is:          rest.ErrRateLimited,
+			expected:    "failed to client.Do: StatusCode is 429",
+			rateLimited: true,
+		},
+		{
+			name:     "Statistics pending",
+			status:   http.StatusAccepted,
+			body:     `{}`,
+			is:       rest.ErrStatsPending,
+			isNot:    []error{rest.ErrNotFound},
+			expected: "failed to client.Do: StatusCode is 202",
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			req, err := http.NewRequest("GET", "https://api.github.com/repos/kokoichi206/utils", nil)
+			require.NoError(t, err)
+			resp := &http.Response{
+				StatusCode: tc.status,
+				Header:     http.Header{},
+				Body:       ioutil.NopCloser(strings.NewReader(tc.body)),
+				Request:    req,
+			}
+			for key, value := range tc.header {
+				resp.Header.Set(key, value)
+			}
+			start := time.Now()
+
+			// Act
+			err = rest.NewStatusError(resp)
+
+			// Assert
+			require.Equal(t, tc.expected, err.Error())
+			require.True(t, errors.Is(err, tc.is))
+			require.True(t, errors.Is(fmt.Errorf("wrapped: %w", err), tc.is))
+			for _, target := range tc.isNot {
+				require.False(t, errors.Is(err, target), target)
+			}
+			require.True(t, rest.IsStatus(err, tc.status))
+			require.False(t, rest.IsStatus(err, http.StatusInternalServerError))
+
+			var re *rest.RateLimitError
+			require.Equal(t, tc.rateLimited, errors.As(err, &re))
+			if re != nil {
+				require.Equal(t, req.URL.String(), re.URL)
+				switch {
+				case tc.reset != 0:
+					require.Equal(t, tc.reset, re.Reset.Unix())
+				case tc.header["Retry-After"] != "":
+					require.WithinDuration(t, start.Add(time.Minute), re.Reset, 5*time.Second)
+				default:
+					require.True(t, re.Reset.IsZero())
+				}
+			}
+		})
+	}
+}
+
+func TestGetStatusError(t *testing.T) {
+
+	// Arrange
+	calls := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls += 1
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"message": "404 Project Not Found"}`))
+	}))
+	defer ts.Close()
+
+	// Act
+	_, _, err := rest.Get(ts.Client(), ts.URL+"/api/v4/projects/1", http.Header{})
+
+	// Assert
+	require.Equal(t, 1, calls)
+	require.True(t, errors.Is(err, rest.ErrNotFound))
+	var se *rest.StatusError
+	require.True(t, errors.As(err, &se))
+	require.Equal(t, ts.URL+"/api/v4/projects/1", se.URL)
+	require.Equal(t, "404 Project Not Found", se.Message)
+}
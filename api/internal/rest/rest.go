@@ -0,0 +1,70 @@
+// Package rest has helpers to call REST APIs of git hosting providers, and errors of their responses.
+package rest
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// Call GET method with the request header and return the body and the header of the response.
+// Server errors are retried, but user's errors (4xx) are not.
+func Get(client *http.Client, URL string, header http.Header) ([]byte, http.Header, error) {
+
+	retries := 3
+
+	req, err := NewRequest("GET", URL)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to http.NewRequest: %w", err)
+	}
+
+	// Set request header.
+	for key, values := range header {
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+
+	var resp *http.Response
+	success := false
+	for retries > 0 {
+		// > If the returned error is nil, the Response will contain a non-nil
+		// > Body which the user is expected to close.
+		resp, err = client.Do(req)
+
+		if err != nil {
+			// Invalid URL (Like different scheme) etc.
+			retries -= 1
+			continue
+		}
+
+		if resp.StatusCode == http.StatusOK {
+			// Success!
+			success = true
+			break
+		}
+
+		if resp.StatusCode/100 == 4 {
+			// If the StatusCode starts with 4, it is user's error,
+			// so it should not be retried.
+			err := NewStatusError(resp)
+			resp.Body.Close()
+			return nil, nil, err
+		}
+		resp.Body.Close()
+
+		retries -= 1
+	}
+
+	if !success {
+		return nil, nil, fmt.Errorf("failed to client.Do after several retries.")
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	defer resp.Body.Close()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to io.ReadAll: %w", err)
+	}
+
+	return body, resp.Header, nil
+}
@@ -0,0 +1,69 @@
+// Package resttest has a stand-in server of REST APIs for tests of the backends.
+package resttest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+)
+
+// Response of the test server.
+type Response struct {
+	StatusCode int
+	Header     map[string]string
+	// '{{URL}}' in the body is replaced with the URL of the test server.
+	Body string
+}
+
+// Query parameter of pages, and its value of the first page.
+type Page struct {
+	Param string
+	First string
+}
+
+// Stand-in server which responds by the escaped path and the page.
+// Responses of pages other than the first one are keyed by '<path>?<param>=<value>'.
+type Server struct {
+	*httptest.Server
+	Responses map[string]Response
+	// Header of the last request.
+	RequestHeader http.Header
+	// Requested URLs (path and query).
+	Requests []string
+}
+
+// Start the server, which responds with notFound for unknown paths.
+func NewServer(notFound string, pages ...Page) *Server {
+	s := &Server{Responses: map[string]Response{}}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Save passed header and requested URL
+		s.RequestHeader = r.Header
+		s.Requests = append(s.Requests, r.URL.RequestURI())
+
+		key := r.URL.EscapedPath()
+		for _, p := range pages {
+			if value := r.URL.Query().Get(p.Param); value != "" && value != p.First {
+				key += "?" + p.Param + "=" + value
+			}
+		}
+		resp, ok := s.Responses[key]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(notFound))
+			return
+		}
+		for k, v := range resp.Header {
+			w.Header().Set(k, v)
+		}
+		w.WriteHeader(resp.StatusCode)
+		w.Write([]byte(strings.ReplaceAll(resp.Body, "{{URL}}", s.URL)))
+	}))
+	return s
+}
+
+// Clear the responses and the requests.
+func (s *Server) Reset() {
+	s.Responses = map[string]Response{}
+	s.RequestHeader = nil
+	s.Requests = nil
+}
@@ -0,0 +1,136 @@
+package local
+
+import (
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/kokoichi206/go-git-stats/api"
+	"github.com/kokoichi206/go-git-stats/util"
+)
+
+// struct that implements ApiCaller with local git repositories.
+// FullName of each repository is its path.
+type Api struct {
+	config util.Config
+}
+
+func New(config util.Config) api.ApiCaller {
+	return &Api{
+		config: config,
+	}
+}
+
+// Lists git repositories at the configured path.
+// Local repositories have no owner, so the userName is ignored.
+func (a *Api) ListPublicRepositories(userName string) ([]api.Repository, error) {
+	return a.ListRepositoriesForAuthenticatedUser()
+}
+
+// Lists git repositories under the configured path.
+// Directories which can not be read are skipped.
+func (a *Api) ListRepositoriesForAuthenticatedUser() ([]api.Repository, error) {
+
+	paths, err := Discover(a.config.LocalPath, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	repositories := make([]api.Repository, 0, len(paths))
+	for i, path := range paths {
+		repositories = append(repositories, newRepository(i+1, path))
+	}
+
+	return repositories, nil
+}
+
+// Lists languages of the files at HEAD of the repository.
+// The value shown for each language is the number of bytes like GitHub.
+func (a *Api) Languages(fullName string) (map[string]int, error) {
+	return readLanguages(a.path(fullName))
+}
+
+// Get the weekly commit activity of the repository from its git history.
+// The result has the same shape as GitHub statistics.
+func (a *Api) WeeklyCommitActivity(fullName string) ([]api.CodeFrequency, error) {
+
+	stats, err := CommitStats(a.path(fullName), a.config.Identities, Filter{})
+	if err != nil {
+		return nil, err
+	}
+
+	return api.WeeklyCodeFrequency(stats), nil
+}
+
+// Get the weekly commit count of the repository for the last 52 weeks.
+// The owner is the person of user.email configured in the repository.
+func (a *Api) Participation(fullName string) (api.Participation, error) {
+
+	path := a.path(fullName)
+	stats, err := CommitStats(path, a.config.Identities, Filter{})
+	if err != nil {
+		return api.Participation{}, err
+	}
+
+	// user.email may not be configured.
+	out, _ := runGit(path, "config", "user.email")
+	owner := ""
+	if email := strings.TrimSpace(out); email != "" {
+		owner = a.config.Identities.Author(email)
+	}
+
+	return participation(stats, time.Now(), owner), nil
+}
+
+// Count commits of the 52 weeks until now (oldest week first).
+// The weeks are the same as GitHub statistics, and the last one also has the commits of today.
+func participation(stats []api.CommitStat, now time.Time, owner string) api.Participation {
+
+	const weeks = 52
+
+	p := api.Participation{
+		All:   make([]int, weeks),
+		Owner: make([]int, weeks),
+	}
+	first := api.ParticipationWeekStart(now, 0, weeks)
+	for _, s := range stats {
+		if s.Time.Before(first) || s.Time.After(now) {
+			continue
+		}
+		i := int(s.Time.Sub(first).Hours() / 24 / 7)
+		if i >= weeks {
+			i = weeks - 1
+		}
+		p.All[i] += 1
+		if owner != "" && s.Author == owner {
+			p.Owner[i] += 1
+		}
+	}
+
+	return p
+}
+
+// Resolve FullName to the path of the repository.
+func (a *Api) path(fullName string) string {
+	if filepath.IsAbs(fullName) {
+		return fullName
+	}
+	return filepath.Join(a.config.LocalPath, fullName)
+}
+
+// Create a repository entry of the path.
+func newRepository(id int, path string) api.Repository {
+
+	r := api.Repository{
+		ID: id,
+		// Local repositories are not published anywhere.
+		Private:  true,
+		Name:     filepath.Base(path),
+		FullName: path,
+	}
+	if languages, err := readLanguages(path); err == nil {
+		r.Language = primaryLanguage(languages)
+	}
+
+	return r
+}
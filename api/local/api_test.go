@@ -0,0 +1,369 @@
+package local_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kokoichi206/go-git-stats/api"
+	"github.com/kokoichi206/go-git-stats/api/local"
+	"github.com/kokoichi206/go-git-stats/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListRepositories(t *testing.T) {
+
+	root := t.TempDir()
+
+	first := NewTestRepository(t, filepath.Join(root, "first"))
+	first.Commit("main.go", "package main\n", "owner@example.com", time.Now())
+	second := NewTestRepository(t, filepath.Join(root, "second"))
+	second.Commit("main.py", "print(1)\nprint(2)\n", "owner@example.com", time.Now())
+	// Not a git repository
+	require.NoError(t, ioutil.WriteFile(filepath.Join(root, "README.md"), []byte("# readme"), 0o644))
+
+	testCases := []struct {
+		name      string
+		path      string
+		assertion func(t *testing.T, err error, repositories []api.Repository)
+	}{
+		{
+			name: "OK directory of repositories",
+			path: root,
+			assertion: func(t *testing.T, err error, repositories []api.Repository) {
+				require.NoError(t, err)
+				t.Log(repositories)
+				require.Equal(t, 2, len(repositories))
+
+				require.Equal(t, "first", repositories[0].Name)
+				require.Equal(t, filepath.Join(root, "first"), repositories[0].FullName)
+				require.Equal(t, "Go", repositories[0].Language)
+				require.Equal(t, "second", repositories[1].Name)
+				require.Equal(t, "Python", repositories[1].Language)
+			},
+		},
+		{
+			name: "OK repository itself",
+			path: filepath.Join(root, "second"),
+			assertion: func(t *testing.T, err error, repositories []api.Repository) {
+				require.NoError(t, err)
+				require.Equal(t, 1, len(repositories))
+				require.Equal(t, "second", repositories[0].Name)
+			},
+		},
+		{
+			name: "Error not found",
+			path: filepath.Join(root, "not-found"),
+			assertion: func(t *testing.T, err error, repositories []api.Repository) {
+				require.Error(t, err)
+				require.Nil(t, repositories)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			a := local.New(util.Config{Backend: util.BackendLocal, LocalPath: tc.path})
+
+			// Act
+			repositories, err := a.ListRepositoriesForAuthenticatedUser()
+
+			// Assert
+			tc.assertion(t, err, repositories)
+		})
+	}
+}
+
+func TestWeeklyCommitActivity(t *testing.T) {
+
+	root := t.TempDir()
+
+	r := NewTestRepository(t, root)
+	r.Commit("main.go", "package main\n\nfunc main() {}\n", "owner@example.com", time.Date(2022, 8, 1, 12, 0, 0, 0, time.UTC))
+	r.Commit("main.go", "package main\n", "owner@example.com", time.Date(2022, 8, 3, 12, 0, 0, 0, time.UTC))
+	r.Commit("util.go", "package main\n\nvar a = 1\n", "someone@example.com", time.Date(2022, 8, 10, 12, 0, 0, 0, time.UTC))
+	// Binary files are not counted
+	r.Commit("image.png", "\x00\x01\x02", "owner@example.com", time.Date(2022, 8, 10, 13, 0, 0, 0, time.UTC))
+
+	a := local.New(util.Config{Backend: util.BackendLocal, LocalPath: root})
+
+	// Act
+	frequencies, err := a.WeeklyCommitActivity(root)
+
+	// Assert
+	require.NoError(t, err)
+	t.Log(frequencies)
+	require.Equal(t, []api.CodeFrequency{
+		{Time: 1659830400, Additions: 3, Deletions: 0},
+		{Time: 1659225600, Additions: 3, Deletions: -2},
+	}, frequencies)
+
+	// Relative path from the configured path
+	_, err = a.WeeklyCommitActivity("not-found")
+	require.Error(t, err)
+	require.True(t, strings.Contains(err.Error(), "failed to git rev-parse"))
+}
+
+func TestRepositoryWithoutCommits(t *testing.T) {
+
+	root := t.TempDir()
+	NewTestRepository(t, root)
+	a := local.New(util.Config{Backend: util.BackendLocal, LocalPath: root})
+
+	// Act
+	repositories, err := a.ListRepositoriesForAuthenticatedUser()
+	require.NoError(t, err)
+	frequencies, err := a.WeeklyCommitActivity(root)
+	require.NoError(t, err)
+	languages, err := a.Languages(root)
+
+	// Assert
+	require.NoError(t, err)
+	require.Equal(t, 1, len(repositories))
+	require.Empty(t, frequencies)
+	require.Empty(t, languages)
+}
+
+func TestParticipation(t *testing.T) {
+
+	root := t.TempDir()
+
+	now := time.Now()
+	r := NewTestRepository(t, root)
+	r.Commit("a.go", "package a\n", "owner@example.com", now.AddDate(0, 0, -14))
+	r.Commit("b.go", "package a\n", "someone@example.com", now.AddDate(0, 0, -14))
+	r.Commit("c.go", "package a\n", "OWNER@example.com", now)
+	// Older than 52 weeks
+	r.Commit("d.go", "package a\n", "owner@example.com", now.AddDate(-2, 0, 0))
+
+	a := local.New(util.Config{Backend: util.BackendLocal, LocalPath: root})
+
+	// Act
+	participation, err := a.Participation(root)
+
+	// Assert
+	require.NoError(t, err)
+	require.Equal(t, 52, len(participation.All))
+	require.Equal(t, 52, len(participation.Owner))
+	require.Equal(t, 1, participation.All[51])
+	require.Equal(t, 1, participation.Owner[51])
+	require.Equal(t, 2, participation.All[50])
+	require.Equal(t, 1, participation.Owner[50])
+
+	all := 0
+	for _, count := range participation.All {
+		all += count
+	}
+	require.Equal(t, 3, all)
+}
+
+func TestLanguages(t *testing.T) {
+
+	root := t.TempDir()
+
+	r := NewTestRepository(t, root)
+	r.Commit("main.go", "package main\n", "owner@example.com", time.Now())
+	r.Commit("scripts/run.sh", "echo 1\n", "owner@example.com", time.Now())
+	r.Commit("Makefile", "all:\n", "owner@example.com", time.Now())
+	// Not counted
+	r.Commit("README.md", "# readme\n", "owner@example.com", time.Now())
+	r.Commit("vendor/lib/lib.go", "package lib\n", "owner@example.com", time.Now())
+
+	a := local.New(util.Config{Backend: util.BackendLocal, LocalPath: root})
+
+	// Act
+	languages, err := a.Languages(root)
+
+	// Assert
+	require.NoError(t, err)
+	require.Equal(t, map[string]int{"Go": 13, "Shell": 7, "Makefile": 5}, languages)
+}
+
+func TestDiscover(t *testing.T) {
+
+	root := t.TempDir()
+	NewTestRepository(t, filepath.Join(root, "a"))
+	NewTestRepository(t, filepath.Join(root, "group", "b"))
+	// Nested repository
+	NewTestRepository(t, filepath.Join(root, "group", "b", "tools", "c"))
+	// Not searched
+	NewTestRepository(t, filepath.Join(root, "a", "vendor", "d"))
+	NewTestRepository(t, filepath.Join(root, "node_modules", "e"))
+
+	// Act
+	paths, err := local.Discover(root, nil)
+
+	// Assert
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		filepath.Join(root, "a"),
+		filepath.Join(root, "group", "b"),
+		filepath.Join(root, "group", "b", "tools", "c"),
+	}, paths)
+}
+
+func TestDiscoverUnreadableDirectory(t *testing.T) {
+
+	if os.Geteuid() == 0 {
+		t.Skip("root can read every directory")
+	}
+
+	root := t.TempDir()
+	NewTestRepository(t, filepath.Join(root, "a"))
+	NewTestRepository(t, filepath.Join(root, "secret", "b"))
+	require.NoError(t, os.Chmod(filepath.Join(root, "secret"), 0o000))
+	defer os.Chmod(filepath.Join(root, "secret"), 0o755)
+
+	// Act
+	skipped := map[string]error{}
+	paths, err := local.Discover(root, func(path string, err error) {
+		skipped[path] = err
+	})
+
+	// Assert
+	require.NoError(t, err)
+	require.Equal(t, []string{filepath.Join(root, "a")}, paths)
+	require.Len(t, skipped, 1)
+	require.Error(t, skipped[filepath.Join(root, "secret")])
+}
+
+func TestCommitStatsWithIdentities(t *testing.T) {
+
+	root := t.TempDir()
+	day := time.Date(2022, 8, 10, 12, 0, 0, 0, time.UTC)
+
+	r := NewTestRepository(t, root)
+	r.Commit("a.go", "1\n", "old@example.com", day)
+	r.Commit("b.go", "1\n2\n", "me@work.example.com", day)
+	r.Commit("c.go", "1\n2\n3\n", "52474650+kokoichi206@users.noreply.github.com", day)
+	r.Commit("d.go", "1\n2\n3\n4\n", "someone@example.com", day)
+	// .mailmap of the repository maps old email to the personal one.
+	r.Commit(".mailmap", "Kokoichi <me@example.com> <old@example.com>\n", "someone@example.com", day)
+
+	identities := util.Identities{
+		People: []util.Person{
+			{
+				Name:   "kokoichi206",
+				Emails: []string{"me@example.com", "me@work.example.com"},
+				Logins: []string{"kokoichi206"},
+			},
+		},
+	}
+
+	testCases := []struct {
+		name      string
+		filter    local.Filter
+		assertion func(t *testing.T, err error, stats []api.CommitStat)
+	}{
+		{
+			name:   "OK all authors",
+			filter: local.Filter{},
+			assertion: func(t *testing.T, err error, stats []api.CommitStat) {
+				require.NoError(t, err)
+				authors := map[string]int{}
+				for _, s := range stats {
+					authors[s.Author] += s.Additions
+				}
+				require.Equal(t, map[string]int{"kokoichi206": 6, "someone@example.com": 5}, authors)
+			},
+		},
+		{
+			name:   "OK by GitHub login",
+			filter: local.Filter{Authors: []string{"kokoichi206"}},
+			assertion: func(t *testing.T, err error, stats []api.CommitStat) {
+				require.NoError(t, err)
+				require.Equal(t, 3, len(stats))
+			},
+		},
+		{
+			name:   "OK by one of the emails",
+			filter: local.Filter{Authors: []string{"ME@example.com"}},
+			assertion: func(t *testing.T, err error, stats []api.CommitStat) {
+				require.NoError(t, err)
+				require.Equal(t, 3, len(stats))
+			},
+		},
+		{
+			name:   "OK by author name of git after mailmap",
+			filter: local.Filter{Authors: []string{"kokoichi"}},
+			assertion: func(t *testing.T, err error, stats []api.CommitStat) {
+				require.NoError(t, err)
+				require.Equal(t, 1, len(stats))
+				require.Equal(t, "kokoichi206", stats[0].Author)
+				require.Equal(t, 1, stats[0].Additions)
+			},
+		},
+		{
+			name:   "OK by unknown email",
+			filter: local.Filter{Authors: []string{"someone@example.com"}},
+			assertion: func(t *testing.T, err error, stats []api.CommitStat) {
+				require.NoError(t, err)
+				require.Equal(t, 2, len(stats))
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			stats, err := local.CommitStats(root, identities, tc.filter)
+
+			// Assert
+			tc.assertion(t, err, stats)
+		})
+	}
+}
+
+func TestCommitStatsWithMailmapFile(t *testing.T) {
+
+	root := t.TempDir()
+	r := NewTestRepository(t, filepath.Join(root, "repo"))
+	r.Commit("a.go", "1\n", "old@example.com", time.Now())
+
+	mailmap := filepath.Join(root, "mailmap")
+	require.NoError(t, ioutil.WriteFile(mailmap, []byte("<new@example.com> <old@example.com>\n"), 0o644))
+
+	// Act
+	stats, err := local.CommitStats(filepath.Join(root, "repo"), util.Identities{Mailmap: mailmap}, local.Filter{})
+
+	// Assert
+	require.NoError(t, err)
+	require.Equal(t, 1, len(stats))
+	require.Equal(t, "new@example.com", stats[0].Author)
+}
+
+func TestParticipationWithIdentities(t *testing.T) {
+
+	root := t.TempDir()
+	r := NewTestRepository(t, root)
+	r.Commit("a.go", "package a\n", "owner@example.com", time.Now())
+	r.Commit("b.go", "package a\n", "owner@work.example.com", time.Now())
+	r.Commit("c.go", "package a\n", "someone@example.com", time.Now())
+
+	config := util.Config{
+		Backend:   util.BackendLocal,
+		LocalPath: root,
+		Identities: util.Identities{
+			People: []util.Person{
+				{Name: "owner", Emails: []string{"owner@example.com", "owner@work.example.com"}},
+			},
+		},
+	}
+	a := local.New(config)
+
+	// Act
+	participation, err := a.Participation(root)
+
+	// Assert
+	require.NoError(t, err)
+	require.Equal(t, 3, participation.All[51])
+	require.Equal(t, 2, participation.Owner[51])
+}
@@ -0,0 +1,54 @@
+package local
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Find every git repository under the root directory (including the root itself).
+// Directories of third party codes (vendor, node_modules) are not searched.
+// Directories which can not be read are skipped, and their errors are passed to skip (if not nil).
+func Discover(root string, skip func(path string, err error)) ([]string, error) {
+
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to filepath.Abs: %w", err)
+	}
+
+	var paths []string
+	err = filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			if path == root {
+				return err
+			}
+			if skip != nil {
+				skip(path, err)
+			}
+			// Walk calls again for the directory which can not be read.
+			if info != nil && info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !info.IsDir() {
+			return nil
+		}
+
+		name := info.Name()
+		if path != root && (name == ".git" || vendoredDirectories[name]) {
+			return filepath.SkipDir
+		}
+
+		// .git is a file for worktrees and submodules.
+		if _, err := os.Stat(filepath.Join(path, ".git")); err == nil {
+			paths = append(paths, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to filepath.Walk: %w", err)
+	}
+
+	return paths, nil
+}
@@ -0,0 +1,68 @@
+package local
+
+import (
+	"strings"
+	"time"
+
+	"github.com/kokoichi206/go-git-stats/api"
+	"github.com/kokoichi206/go-git-stats/util"
+)
+
+// Conditions to select commits.
+type Filter struct {
+	// Names, emails or GitHub logins of authors which are resolved by identities,
+	// or author names of git (after mailmap) which are compared case-insensitively.
+	// All authors are selected if empty.
+	Authors []string
+	// Commits at or after Since. No limit if zero.
+	Since time.Time
+	// Commits before Until. No limit if zero.
+	Until time.Time
+}
+
+// Returns whether the commit of the canonical author matches the filter.
+func (f Filter) match(c commit, author string, identities util.Identities) bool {
+
+	if !f.Since.IsZero() && c.Time.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && !c.Time.Before(f.Until) {
+		return false
+	}
+
+	if len(f.Authors) == 0 {
+		return true
+	}
+	for _, key := range f.Authors {
+		if identities.Resolve(key) == author || strings.EqualFold(key, c.Name) {
+			return true
+		}
+	}
+	return false
+}
+
+// Read line changes of the commits in the repository which match the filter.
+// Authors of the commits are mapped to canonical people by mailmap and identities.
+func CommitStats(path string, identities util.Identities, filter Filter) ([]api.CommitStat, error) {
+
+	commits, err := readCommits(path, identities.Mailmap)
+	if err != nil {
+		return nil, err
+	}
+
+	var stats []api.CommitStat
+	for _, c := range commits {
+		author := identities.Author(c.Email)
+		if !filter.match(c, author, identities) {
+			continue
+		}
+		stats = append(stats, api.CommitStat{
+			Author:    author,
+			Time:      c.Time,
+			Additions: c.Additions,
+			Deletions: c.Deletions,
+		})
+	}
+
+	return stats, nil
+}
@@ -0,0 +1,145 @@
+package local
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// Separates commits in the output of git log.
+	recordSeparator = "\x1e"
+	// Separates fields of a commit header in the output of git log.
+	fieldSeparator = "\x1f"
+)
+
+// A commit read from local git history.
+type commit struct {
+	Time      time.Time
+	Email     string
+	Name      string
+	Additions int
+	Deletions int
+}
+
+// Run git in the specified repository and return its standard output.
+func runGit(path string, args ...string) (string, error) {
+
+	cmd := exec.Command("git", append([]string{"-C", path}, args...)...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		// Name the subcommand (skipping '-c <name>=<value>' options) in the error.
+		subcommand := args[0]
+		for i := 0; i+2 < len(args) && args[i] == "-c"; i += 2 {
+			subcommand = args[i+2]
+		}
+		return "", fmt.Errorf("failed to git %s: %w: %s", subcommand, err, strings.TrimSpace(stderr.String()))
+	}
+
+	return stdout.String(), nil
+}
+
+// Returns whether the repository has any commits.
+// git log and git ls-tree fail in repositories without them.
+func hasCommits(path string) (bool, error) {
+
+	_, err := runGit(path, "rev-parse", "--verify", "--quiet", "HEAD")
+	// HEAD is not found (but the repository is).
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// Read all commits (except merges) with the number of added and deleted lines.
+// Emails and names of authors are mapped by .mailmap of the repository and the mailmap file (if given).
+// Repositories without any commits have no commits.
+func readCommits(path string, mailmap string) ([]commit, error) {
+
+	ok, err := hasCommits(path)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, nil
+	}
+
+	var args []string
+	if mailmap != "" {
+		args = append(args, "-c", "mailmap.file="+mailmap)
+	}
+	args = append(args,
+		"log", "--no-merges", "--numstat",
+		// %aE and %aN respect mailmap.
+		fmt.Sprintf("--format=%s%%at%s%%aE%s%%aN", recordSeparator, fieldSeparator, fieldSeparator),
+	)
+
+	out, err := runGit(path, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseLog(out)
+}
+
+// Parse the output of git log --numstat in the format of readCommits.
+func parseLog(out string) ([]commit, error) {
+
+	var commits []commit
+	for _, record := range strings.Split(out, recordSeparator) {
+		if strings.TrimSpace(record) == "" {
+			continue
+		}
+
+		lines := strings.Split(record, "\n")
+		header := strings.Split(lines[0], fieldSeparator)
+		if len(header) != 3 {
+			return nil, fmt.Errorf("failed to parse git log: unexpected header '%s'", lines[0])
+		}
+
+		unix, err := strconv.ParseInt(header[0], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to strconv.ParseInt: %w", err)
+		}
+
+		c := commit{
+			Time:  time.Unix(unix, 0),
+			Email: header[1],
+			Name:  header[2],
+		}
+
+		for _, line := range lines[1:] {
+			fields := strings.SplitN(line, "\t", 3)
+			if len(fields) != 3 {
+				continue
+			}
+			// Binary files are shown as '-'.
+			additions, err := strconv.Atoi(fields[0])
+			if err != nil {
+				continue
+			}
+			deletions, err := strconv.Atoi(fields[1])
+			if err != nil {
+				continue
+			}
+			c.Additions += additions
+			c.Deletions += deletions
+		}
+
+		commits = append(commits, c)
+	}
+
+	return commits, nil
+}
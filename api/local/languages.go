@@ -0,0 +1,126 @@
+package local
+
+import (
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Languages detected by file extensions.
+// Data, configuration and documentation files are not counted like GitHub does.
+var extensionLanguages = map[string]string{
+	".c":     "C",
+	".h":     "C",
+	".cc":    "C++",
+	".cpp":   "C++",
+	".hpp":   "C++",
+	".cs":    "C#",
+	".css":   "CSS",
+	".dart":  "Dart",
+	".go":    "Go",
+	".html":  "HTML",
+	".java":  "Java",
+	".js":    "JavaScript",
+	".jsx":   "JavaScript",
+	".mjs":   "JavaScript",
+	".kt":    "Kotlin",
+	".kts":   "Kotlin",
+	".lua":   "Lua",
+	".m":     "Objective-C",
+	".php":   "PHP",
+	".pl":    "Perl",
+	".py":    "Python",
+	".r":     "R",
+	".rb":    "Ruby",
+	".rs":    "Rust",
+	".scala": "Scala",
+	".scss":  "SCSS",
+	".sh":    "Shell",
+	".bash":  "Shell",
+	".zsh":   "Shell",
+	".swift": "Swift",
+	".ts":    "TypeScript",
+	".tsx":   "TypeScript",
+	".vue":   "Vue",
+}
+
+// Languages detected by file names.
+var filenameLanguages = map[string]string{
+	"Dockerfile": "Dockerfile",
+	"Makefile":   "Makefile",
+}
+
+// Directories which contain third party codes.
+var vendoredDirectories = map[string]bool{
+	"vendor":       true,
+	"node_modules": true,
+}
+
+// Returns the language of the file, or empty string if unknown.
+func detectLanguage(path string) string {
+
+	for _, dir := range strings.Split(filepath.ToSlash(filepath.Dir(path)), "/") {
+		if vendoredDirectories[dir] {
+			return ""
+		}
+	}
+
+	if language, ok := filenameLanguages[filepath.Base(path)]; ok {
+		return language
+	}
+
+	return extensionLanguages[strings.ToLower(filepath.Ext(path))]
+}
+
+// Count bytes of each language in the files at HEAD.
+func readLanguages(path string) (map[string]int, error) {
+
+	ok, err := hasCommits(path)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return map[string]int{}, nil
+	}
+
+	// Each line is '<mode> <type> <object> <size>\t<path>'.
+	out, err := runGit(path, "ls-tree", "-r", "-l", "HEAD")
+	if err != nil {
+		return nil, err
+	}
+
+	languages := make(map[string]int)
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.SplitN(line, "\t", 2)
+		if len(fields) != 2 {
+			continue
+		}
+		meta := strings.Fields(fields[0])
+		if len(meta) != 4 || meta[1] != "blob" {
+			continue
+		}
+		size, err := strconv.Atoi(meta[3])
+		if err != nil {
+			continue
+		}
+
+		if language := detectLanguage(fields[1]); language != "" {
+			languages[language] += size
+		}
+	}
+
+	return languages, nil
+}
+
+// Returns the language with the most bytes.
+func primaryLanguage(languages map[string]int) string {
+
+	primary := ""
+	for language, bytes := range languages {
+		if bytes > languages[primary] || (bytes == languages[primary] && language < primary) {
+			primary = language
+		}
+	}
+
+	return primary
+}
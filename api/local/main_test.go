@@ -0,0 +1,72 @@
+package local_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// Git repository created in a temporary directory for testing.
+type TestRepository struct {
+	t    *testing.T
+	path string
+}
+
+// Initialize a git repository at dir.
+func NewTestRepository(t *testing.T, dir string) *TestRepository {
+	t.Helper()
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	r := &TestRepository{t: t, path: dir}
+	r.git(time.Now(), "init", "-q")
+	r.git(time.Now(), "config", "user.email", "owner@example.com")
+	r.git(time.Now(), "config", "user.name", "owner")
+
+	return r
+}
+
+// Write the file and commit it as the author at the time.
+func (r *TestRepository) Commit(file, content, author string, at time.Time) {
+	r.t.Helper()
+
+	path := filepath.Join(r.path, file)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		r.t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(path, []byte(content), 0o644); err != nil {
+		r.t.Fatal(err)
+	}
+	r.git(at, "add", "-A")
+	r.git(at, "commit", "-q", "-m", "commit "+file, "--author", fmt.Sprintf("%s <%s>", author, author))
+}
+
+func (r *TestRepository) git(at time.Time, args ...string) {
+	r.t.Helper()
+
+	cmd := exec.Command("git", append([]string{"-C", r.path}, args...)...)
+	date := at.Format(time.RFC3339)
+	cmd.Env = append(os.Environ(),
+		"GIT_AUTHOR_DATE="+date,
+		"GIT_COMMITTER_DATE="+date,
+		"GIT_CONFIG_NOSYSTEM=1",
+		"HOME="+r.path,
+	)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		r.t.Fatalf("git %v: %v: %s", args, err, out)
+	}
+}
+
+func TestMain(m *testing.M) {
+	// Tests of the local backend need git.
+	if _, err := exec.LookPath("git"); err != nil {
+		fmt.Println("git is not found, skip tests")
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
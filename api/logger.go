@@ -0,0 +1,191 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/kokoichi206/go-git-stats/api/internal/rest"
+	"github.com/kokoichi206/go-git-stats/util"
+)
+
+// Log of a request made by the Api methods.
+type RequestLog struct {
+	Time   time.Time
+	Method string
+	URL    string
+	// Status code of the response, zero if the request failed.
+	Status  int
+	Latency time.Duration
+	// Remaining requests of the rate limit, -1 if unknown.
+	RateLimitRemaining int
+	// Attempt of the request, which is larger than 1 when retried.
+	Attempt int
+	// Whether the response comes from the cache.
+	CacheHit bool
+	// Error of the request, nil if the response is received.
+	Err error
+}
+
+// Logger of requests made by the Api methods.
+type Logger interface {
+	LogRequest(log RequestLog)
+}
+
+// Set the logger of requests, nil stops logging.
+func (a *Api) SetLogger(logger Logger) {
+	a.log.set(logger)
+}
+
+// Logger which writes a line of text per request.
+type textLogger struct {
+	mutex *sync.Mutex
+	w     io.Writer
+}
+
+// Returns the logger which writes a line of text per request to w.
+// Secrets like tokens are redacted.
+func NewTextLogger(w io.Writer) Logger {
+	return &textLogger{mutex: &sync.Mutex{}, w: w}
+}
+
+func (l *textLogger) LogRequest(log RequestLog) {
+
+	status := strconv.Itoa(log.Status)
+	if log.Err != nil {
+		status = fmt.Sprintf("error=%q", log.Err.Error())
+	}
+	remaining := "-"
+	if log.RateLimitRemaining >= 0 {
+		remaining = strconv.Itoa(log.RateLimitRemaining)
+	}
+	cache := "miss"
+	if log.CacheHit {
+		cache = "hit"
+	}
+
+	line := fmt.Sprintf("%s %s %s %s latency=%s remaining=%s attempt=%d cache=%s\n",
+		log.Time.Format("2006-01-02T15:04:05.000Z07:00"), log.Method, log.URL, status,
+		log.Latency.Round(time.Millisecond), remaining, log.Attempt, cache)
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	io.WriteString(l.w, util.Redact(line))
+}
+
+// Logger which writes a JSON object per line per request.
+type jsonLogger struct {
+	mutex *sync.Mutex
+	w     io.Writer
+}
+
+// Returns the logger which writes a JSON object per request to w (JSON Lines).
+// Secrets like tokens are redacted.
+func NewJSONLogger(w io.Writer) Logger {
+	return &jsonLogger{mutex: &sync.Mutex{}, w: w}
+}
+
+func (l *jsonLogger) LogRequest(log RequestLog) {
+
+	entry := struct {
+		Time               string  `json:"time"`
+		Method             string  `json:"method"`
+		URL                string  `json:"url"`
+		Status             int     `json:"status,omitempty"`
+		LatencyMS          float64 `json:"latency_ms"`
+		RateLimitRemaining *int    `json:"rate_limit_remaining,omitempty"`
+		Attempt            int     `json:"attempt"`
+		CacheHit           bool    `json:"cache_hit"`
+		Error              string  `json:"error,omitempty"`
+	}{
+		Time:      log.Time.Format(time.RFC3339Nano),
+		Method:    log.Method,
+		URL:       log.URL,
+		Status:    log.Status,
+		LatencyMS: float64(log.Latency.Microseconds()) / 1000,
+		Attempt:   log.Attempt,
+		CacheHit:  log.CacheHit,
+	}
+	if log.RateLimitRemaining >= 0 {
+		entry.RateLimitRemaining = &log.RateLimitRemaining
+	}
+	if log.Err != nil {
+		entry.Error = log.Err.Error()
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	io.WriteString(l.w, util.Redact(string(data))+"\n")
+}
+
+// Returns the request of the Api methods, whose attempts are counted while it is retried.
+func newRequest(method, URL string) (*http.Request, error) {
+	return rest.NewRequest(method, URL)
+}
+
+// RoundTripper which logs requests with the logger (if set).
+type logTransport struct {
+	mutex  *sync.RWMutex
+	logger Logger
+	next   http.RoundTripper
+	now    func() time.Time
+}
+
+func newLogTransport(next http.RoundTripper) *logTransport {
+
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &logTransport{mutex: &sync.RWMutex{}, next: next, now: time.Now}
+}
+
+func (t *logTransport) set(logger Logger) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.logger = logger
+}
+
+func (t *logTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+
+	t.mutex.RLock()
+	logger := t.logger
+	t.mutex.RUnlock()
+
+	attempt := rest.CountAttempt(req)
+
+	if logger == nil {
+		return t.next.RoundTrip(req)
+	}
+
+	start := t.now()
+	resp, err := t.next.RoundTrip(req)
+
+	log := RequestLog{
+		Time:               start,
+		Method:             req.Method,
+		URL:                req.URL.String(),
+		Latency:            t.now().Sub(start),
+		RateLimitRemaining: -1,
+		Attempt:            attempt,
+		Err:                err,
+	}
+	if resp != nil {
+		log.Status = resp.StatusCode
+		log.CacheHit = resp.Header.Get(util.CacheHeader) == "hit"
+		if remaining, err := strconv.Atoi(resp.Header.Get("X-RateLimit-Remaining")); err == nil {
+			log.RateLimitRemaining = remaining
+		}
+	}
+	logger.LogRequest(log)
+
+	return resp, err
+}
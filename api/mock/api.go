@@ -1,46 +1,94 @@
 package mock
 
 import (
+	"sync"
+
 	"github.com/kokoichi206/go-git-stats/api"
 	"github.com/kokoichi206/go-git-stats/util"
 )
 
 type MockApi struct {
+	// Guards the fields while the commands call the methods concurrently.
+	mutex               sync.Mutex
 	config              util.Config
 	ListRepos           []api.Repository
 	ListCodeFreq        [][]api.CodeFrequency
 	Error               error
+	RepositoryErrors    map[string]error
+	LanguagesErrors     map[string]error
 	PublicCalled        bool
 	AuthenticatedCalled bool
 	WeeklyCodeCalled    bool
 	PassedFullName      string
+	ParticipationData   api.Participation
+	ParticipationCalled bool
+	ListLanguages       map[string]map[string]int
+	LanguagesCalled     bool
 }
 
 func (a *MockApi) InitMock() {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
 	a.Error = nil
+	a.RepositoryErrors = nil
+	a.LanguagesErrors = nil
 	a.PublicCalled = false
 	a.AuthenticatedCalled = false
 	a.WeeklyCodeCalled = false
+	a.ParticipationCalled = false
+	a.LanguagesCalled = false
 }
 
 func (a *MockApi) ListPublicRepositories(userName string) ([]api.Repository, error) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
 	a.PublicCalled = true
 	return a.ListRepos, a.Error
 }
 
 func (a *MockApi) ListRepositoriesForAuthenticatedUser() ([]api.Repository, error) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
 	a.AuthenticatedCalled = true
 	return a.ListRepos, a.Error
 }
 
+func (a *MockApi) Languages(fullName string) (map[string]int, error) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	a.LanguagesCalled = true
+
+	if a.Error != nil {
+		return nil, a.Error
+	}
+	if err := a.RepositoryErrors[fullName]; err != nil {
+		return nil, err
+	}
+	if err := a.LanguagesErrors[fullName]; err != nil {
+		return nil, err
+	}
+
+	return a.ListLanguages[fullName], nil
+}
+
 func (a *MockApi) WeeklyCommitActivity(fullName string) ([]api.CodeFrequency, error) {
 
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
 	a.WeeklyCodeCalled = true
 	a.PassedFullName = fullName
 
 	if a.Error != nil {
 		return nil, a.Error
 	}
+	if err := a.RepositoryErrors[fullName]; err != nil {
+		return nil, err
+	}
 
 	// Shift ListCodeFreq
 	lcf := a.ListCodeFreq[0]
@@ -48,6 +96,21 @@ func (a *MockApi) WeeklyCommitActivity(fullName string) ([]api.CodeFrequency, er
 	return lcf, a.Error
 }
 
+func (a *MockApi) Participation(fullName string) (api.Participation, error) {
+
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	a.ParticipationCalled = true
+	a.PassedFullName = fullName
+
+	if a.Error != nil {
+		return api.Participation{}, a.Error
+	}
+
+	return a.ParticipationData, nil
+}
+
 func New(config util.Config) *MockApi {
 	return &MockApi{
 		config:              config,
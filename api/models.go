@@ -5,6 +5,8 @@ type Repository struct {
 	Private  bool   `json:"private"`
 	Name     string `json:"name"`
 	FullName string `json:"full_name"`
+	// Primary language of the repository.
+	Language string `json:"language"`
 }
 
 type CodeFrequency struct {
@@ -12,3 +14,9 @@ type CodeFrequency struct {
 	Additions int
 	Deletions int
 }
+
+// Weekly commit counts for the last 52 weeks (oldest week first).
+type Participation struct {
+	All   []int `json:"all"`
+	Owner []int `json:"owner"`
+}
@@ -0,0 +1,154 @@
+// Package multi implements ApiCaller which aggregates several GitHub hosts (or accounts).
+package multi
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/kokoichi206/go-git-stats/api"
+)
+
+// A host (or an account) to aggregate.
+type Host struct {
+	// Label of the host (e.g. github.com, ghe.example.com).
+	Name string
+	Api  api.ApiCaller
+	// Whether repositories of the authenticated user can be listed (the token is set).
+	Authenticated bool
+}
+
+// struct that implements ApiCaller with several hosts.
+// FullName of each repository is labeled with the host: '<host>/<owner>/<repo>'.
+// Repositories with the same '<owner>/<repo>' on several hosts are regarded as mirrors,
+// and only the one on the first host is listed.
+// Several accounts on the same host share the label, and each repository is read
+// with the account which lists it.
+type Api struct {
+	hosts []Host
+	mutex sync.Mutex
+	// Index of the account which lists the repository, keyed by the lower-cased labeled FullName.
+	accounts map[string]int
+}
+
+func New(hosts []Host) api.ApiCaller {
+	return &Api{
+		hosts:    hosts,
+		accounts: map[string]int{},
+	}
+}
+
+// Lists public repositories of the user on all hosts.
+func (a *Api) ListPublicRepositories(userName string) ([]api.Repository, error) {
+	return a.listRepositories(func(h Host) ([]api.Repository, error) {
+		return h.Api.ListPublicRepositories(userName)
+	}, false)
+}
+
+// Lists repositories for the authenticated user on all hosts with the token.
+func (a *Api) ListRepositoriesForAuthenticatedUser() ([]api.Repository, error) {
+	return a.listRepositories(func(h Host) ([]api.Repository, error) {
+		return h.Api.ListRepositoriesForAuthenticatedUser()
+	}, true)
+}
+
+func (a *Api) Languages(fullName string) (map[string]int, error) {
+	var languages map[string]int
+	err := a.route(fullName, func(h Host, name string) (err error) {
+		languages, err = h.Api.Languages(name)
+		return err
+	})
+	return languages, err
+}
+
+func (a *Api) WeeklyCommitActivity(fullName string) ([]api.CodeFrequency, error) {
+	var frequencies []api.CodeFrequency
+	err := a.route(fullName, func(h Host, name string) (err error) {
+		frequencies, err = h.Api.WeeklyCommitActivity(name)
+		return err
+	})
+	return frequencies, err
+}
+
+func (a *Api) Participation(fullName string) (api.Participation, error) {
+	var participation api.Participation
+	err := a.route(fullName, func(h Host, name string) (err error) {
+		participation, err = h.Api.Participation(name)
+		return err
+	})
+	return participation, err
+}
+
+// List repositories of all hosts labeled with the host, except mirrors.
+func (a *Api) listRepositories(list func(h Host) ([]api.Repository, error), authenticated bool) ([]api.Repository, error) {
+
+	var repositories []api.Repository
+	seen := map[string]bool{}
+	for i, h := range a.hosts {
+		if authenticated && !h.Authenticated {
+			continue
+		}
+
+		rs, err := list(h)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", h.Name, err)
+		}
+
+		for _, r := range rs {
+			// Mirrors have the same owner and name.
+			key := strings.ToLower(r.FullName)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+
+			r.FullName = fmt.Sprintf("%s/%s", h.Name, r.FullName)
+			repositories = append(repositories, r)
+
+			a.mutex.Lock()
+			a.accounts[strings.ToLower(r.FullName)] = i
+			a.mutex.Unlock()
+		}
+	}
+
+	return repositories, nil
+}
+
+// Call the host of the FullName with the name without the host label.
+// A listed repository is read with the account which lists it. Otherwise the accounts
+// of the host are tried in order until one finds the repository,
+// and a name without the label of a configured host belongs to the first host.
+func (a *Api) route(fullName string, call func(h Host, name string) error) error {
+
+	i := strings.Index(fullName, "/")
+	if i < 0 {
+		return call(a.hosts[0], fullName)
+	}
+	label, name := fullName[:i], fullName[i+1:]
+
+	a.mutex.Lock()
+	account, ok := a.accounts[strings.ToLower(fullName)]
+	a.mutex.Unlock()
+	if ok {
+		return call(a.hosts[account], name)
+	}
+
+	var err error
+	found := false
+	for _, h := range a.hosts {
+		if !strings.EqualFold(h.Name, label) {
+			continue
+		}
+		found = true
+		// Private repositories of other accounts are not found.
+		if err = call(h, name); !errors.Is(err, api.ErrNotFound) {
+			return err
+		}
+	}
+	if found {
+		return err
+	}
+
+	return call(a.hosts[0], fullName)
+}
@@ -0,0 +1,215 @@
+package multi_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/kokoichi206/go-git-stats/api"
+	"github.com/kokoichi206/go-git-stats/api/mock"
+	"github.com/kokoichi206/go-git-stats/api/multi"
+	"github.com/kokoichi206/go-git-stats/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListRepositories(t *testing.T) {
+
+	github := mock.New(util.Config{})
+	enterprise := mock.New(util.Config{})
+	a := multi.New([]multi.Host{
+		{Name: "github.com", Api: github, Authenticated: true},
+		{Name: "ghe.example.com", Api: enterprise, Authenticated: true},
+	})
+
+	testCases := []struct {
+		name      string
+		setup     func()
+		act       func() ([]api.Repository, error)
+		assertion func(t *testing.T, err error, repositories []api.Repository)
+	}{
+		{
+			name: "OK merged and labeled with the host",
+			setup: func() {
+				github.ListRepos = []api.Repository{
+					{ID: 1, Name: "go-git-stats", FullName: "kokoichi206/go-git-stats"},
+				}
+				enterprise.ListRepos = []api.Repository{
+					{ID: 1, Private: true, Name: "internal-tool", FullName: "team/internal-tool"},
+				}
+			},
+			act: a.ListRepositoriesForAuthenticatedUser,
+			assertion: func(t *testing.T, err error, repositories []api.Repository) {
+				require.NoError(t, err)
+				require.Equal(t, []api.Repository{
+					{ID: 1, Name: "go-git-stats", FullName: "github.com/kokoichi206/go-git-stats"},
+					{ID: 1, Private: true, Name: "internal-tool", FullName: "ghe.example.com/team/internal-tool"},
+				}, repositories)
+				require.True(t, github.AuthenticatedCalled)
+				require.True(t, enterprise.AuthenticatedCalled)
+			},
+		},
+		{
+			name: "OK mirrors are listed once",
+			setup: func() {
+				github.ListRepos = []api.Repository{
+					{ID: 1, Name: "go-git-stats", FullName: "kokoichi206/go-git-stats"},
+				}
+				enterprise.ListRepos = []api.Repository{
+					{ID: 2, Name: "go-git-stats", FullName: "Kokoichi206/go-git-stats"},
+					{ID: 3, Name: "internal-tool", FullName: "team/internal-tool"},
+				}
+			},
+			act: func() ([]api.Repository, error) {
+				return a.ListPublicRepositories("kokoichi206")
+			},
+			assertion: func(t *testing.T, err error, repositories []api.Repository) {
+				require.NoError(t, err)
+				require.Equal(t, []api.Repository{
+					{ID: 1, Name: "go-git-stats", FullName: "github.com/kokoichi206/go-git-stats"},
+					{ID: 3, Name: "internal-tool", FullName: "ghe.example.com/team/internal-tool"},
+				}, repositories)
+				require.True(t, github.PublicCalled)
+				require.True(t, enterprise.PublicCalled)
+			},
+		},
+		{
+			name: "Error of a host",
+			setup: func() {
+				enterprise.Error = errors.New("failed to client.Do: StatusCode is 401")
+			},
+			act: a.ListRepositoriesForAuthenticatedUser,
+			assertion: func(t *testing.T, err error, repositories []api.Repository) {
+				require.Error(t, err)
+				require.Equal(t, "ghe.example.com: failed to client.Do: StatusCode is 401", err.Error())
+				require.Nil(t, repositories)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			tc.setup()
+			defer github.InitMock()
+			defer enterprise.InitMock()
+
+			// Act
+			repositories, err := tc.act()
+
+			// Assert
+			tc.assertion(t, err, repositories)
+		})
+	}
+}
+
+func TestListRepositoriesWithoutToken(t *testing.T) {
+
+	github := mock.New(util.Config{})
+	enterprise := mock.New(util.Config{})
+	a := multi.New([]multi.Host{
+		{Name: "github.com", Api: github, Authenticated: false},
+		{Name: "ghe.example.com", Api: enterprise, Authenticated: true},
+	})
+
+	// Act
+	_, err := a.ListRepositoriesForAuthenticatedUser()
+
+	// Assert
+	require.NoError(t, err)
+	require.False(t, github.AuthenticatedCalled)
+	require.True(t, enterprise.AuthenticatedCalled)
+}
+
+func TestRoute(t *testing.T) {
+
+	github := mock.New(util.Config{})
+	enterprise := mock.New(util.Config{})
+	a := multi.New([]multi.Host{
+		{Name: "github.com", Api: github},
+		{Name: "ghe.example.com", Api: enterprise},
+	})
+
+	testCases := []struct {
+		name     string
+		fullName string
+		expected *mock.MockApi
+		passed   string
+	}{
+		{
+			name:     "GitHub.com",
+			fullName: "github.com/kokoichi206/go-git-stats",
+			expected: github,
+			passed:   "kokoichi206/go-git-stats",
+		},
+		{
+			name:     "GitHub Enterprise Server",
+			fullName: "GHE.example.com/team/internal-tool",
+			expected: enterprise,
+			passed:   "team/internal-tool",
+		},
+		{
+			name:     "Without the host label",
+			fullName: "kokoichi206/go-git-stats",
+			expected: github,
+			passed:   "kokoichi206/go-git-stats",
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			github.PassedFullName = ""
+			enterprise.PassedFullName = ""
+
+			// Act
+			_, err := a.Participation(tc.fullName)
+
+			// Assert
+			require.NoError(t, err)
+			require.Equal(t, tc.passed, tc.expected.PassedFullName)
+			require.True(t, tc.expected.ParticipationCalled)
+			tc.expected.InitMock()
+		})
+	}
+}
+
+func TestRouteAccounts(t *testing.T) {
+
+	// Arrange
+	personal := mock.New(util.Config{})
+	work := mock.New(util.Config{})
+	a := multi.New([]multi.Host{
+		{Name: "github.com", Api: personal, Authenticated: true},
+		{Name: "github.com", Api: work, Authenticated: true},
+	})
+	personal.ListRepos = []api.Repository{{ID: 1, Name: "go-git-stats", FullName: "kokoichi206/go-git-stats"}}
+	work.ListRepos = []api.Repository{{ID: 2, Private: true, Name: "internal-tool", FullName: "team/internal-tool"}}
+	work.ListLanguages = map[string]map[string]int{"team/internal-tool": {"Go": 100}}
+
+	// Act
+	repositories, err := a.ListRepositoriesForAuthenticatedUser()
+	require.NoError(t, err)
+	languages, err := a.Languages("github.com/team/internal-tool")
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, repositories, 2)
+	require.Equal(t, map[string]int{"Go": 100}, languages)
+	require.False(t, personal.LanguagesCalled)
+	require.True(t, work.LanguagesCalled)
+
+	// Repositories which are not listed are tried with the accounts in order.
+	personal.Error = api.ErrNotFound
+	work.ParticipationData = api.Participation{Owner: []int{1}}
+
+	participation, err := a.Participation("github.com/team/other-tool")
+
+	require.NoError(t, err)
+	require.True(t, personal.ParticipationCalled)
+	require.True(t, work.ParticipationCalled)
+	require.Equal(t, "team/other-tool", work.PassedFullName)
+	require.Equal(t, api.Participation{Owner: []int{1}}, participation)
+}
@@ -0,0 +1,172 @@
+// Package oauth implements the OAuth device flow of GitHub to get an access token.
+// For detailed information, see the official documentations:
+// https://docs.github.com/en/apps/oauth-apps/building-oauth-apps/authorizing-oauth-apps#device-flow
+package oauth
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// The interval is increased by this when the server responds 'slow_down'.
+const slowDownInterval = 5 * time.Second
+
+// Default and minimum interval of polling.
+// see: https://www.rfc-editor.org/rfc/rfc8628#section-3.5
+const minInterval = 5 * time.Second
+
+var (
+	// The user denied the authorization.
+	ErrAccessDenied = errors.New("the authorization was denied")
+	// The device code expired before the user authorized.
+	ErrExpired = errors.New("the device code expired")
+)
+
+// Device code and the code for the user to enter.
+type DeviceCode struct {
+	DeviceCode      string `json:"device_code"`
+	UserCode        string `json:"user_code"`
+	VerificationURI string `json:"verification_uri"`
+	// Lifetime of the codes in seconds.
+	ExpiresIn int `json:"expires_in"`
+	// Minimum seconds between polling requests.
+	Interval int `json:"interval"`
+}
+
+// Access token issued by the device flow.
+type Token struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	// Granted scopes separated by commas.
+	Scope string `json:"scope"`
+}
+
+// Device flow of the OAuth app.
+type DeviceFlow struct {
+	// URL of the web site of GitHub (e.g. https://github.com).
+	webURL   string
+	clientID string
+	client   *http.Client
+	sleep    func(time.Duration)
+	now      func() time.Time
+}
+
+func NewDeviceFlow(webURL, clientID string, client *http.Client) *DeviceFlow {
+	return &DeviceFlow{
+		webURL:   strings.TrimSuffix(webURL, "/"),
+		clientID: clientID,
+		client:   client,
+		sleep:    time.Sleep,
+		now:      time.Now,
+	}
+}
+
+// Request the device code and the user code.
+// The scopes are separated by spaces.
+func (f *DeviceFlow) RequestCode(scopes string) (DeviceCode, error) {
+
+	var code DeviceCode
+	err := f.post("/login/device/code", url.Values{
+		"client_id": {f.clientID},
+		"scope":     {scopes},
+	}, &code)
+	if err != nil {
+		return DeviceCode{}, err
+	}
+
+	return code, nil
+}
+
+// Poll the access token until the user authorizes the device (or the code expires).
+// The code expires after ExpiresIn even if the server keeps responding 'authorization_pending'.
+func (f *DeviceFlow) PollToken(code DeviceCode) (Token, error) {
+
+	var deadline time.Time
+	if code.ExpiresIn > 0 {
+		deadline = f.now().Add(time.Duration(code.ExpiresIn) * time.Second)
+	}
+
+	interval := time.Duration(code.Interval) * time.Second
+	for {
+		if interval < minInterval {
+			interval = minInterval
+		}
+		if !deadline.IsZero() && f.now().Add(interval).After(deadline) {
+			return Token{}, ErrExpired
+		}
+		f.sleep(interval)
+
+		var resp struct {
+			Token
+			Error       string `json:"error"`
+			Description string `json:"error_description"`
+			// Interval required by the server after 'slow_down'.
+			Interval int `json:"interval"`
+		}
+		err := f.post("/login/oauth/access_token", url.Values{
+			"client_id":   {f.clientID},
+			"device_code": {code.DeviceCode},
+			"grant_type":  {"urn:ietf:params:oauth:grant-type:device_code"},
+		}, &resp)
+		if err != nil {
+			return Token{}, err
+		}
+
+		switch resp.Error {
+		case "":
+			return resp.Token, nil
+		case "authorization_pending":
+			continue
+		case "slow_down":
+			interval += slowDownInterval
+			if resp.Interval > 0 {
+				interval = time.Duration(resp.Interval) * time.Second
+			}
+		case "expired_token":
+			return Token{}, ErrExpired
+		case "access_denied":
+			return Token{}, ErrAccessDenied
+		default:
+			return Token{}, fmt.Errorf("failed to get the access token: %s: %s", resp.Error, resp.Description)
+		}
+	}
+}
+
+// Call POST method with the form and decode the JSON response.
+func (f *DeviceFlow) post(path string, form url.Values, v interface{}) error {
+
+	req, err := http.NewRequest("POST", f.webURL+path, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("failed to http.NewRequest: %w", err)
+	}
+
+	// Set request header.
+	req.Header.Add("Accept", "application/json")
+	req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to client.Do: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to client.Do: StatusCode is %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to io.ReadAll: %w", err)
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("failed to json.Unmarshal: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,11 @@
+package oauth
+
+import "time"
+
+func (f *DeviceFlow) ExportSetSleep(sleep func(time.Duration)) {
+	f.sleep = sleep
+}
+
+func (f *DeviceFlow) ExportSetNow(now func() time.Time) {
+	f.now = now
+}
@@ -0,0 +1,96 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/kokoichi206/go-git-stats/telemetry"
+)
+
+// User-Agent of requests unless WithUserAgent is given.
+const DefaultUserAgent = "go-git-stats"
+
+// Middleware which wraps the transport to the server (e.g. metrics, fault injection or test doubles).
+type Middleware func(next http.RoundTripper) http.RoundTripper
+
+// Option of New.
+type Option func(*options)
+
+type options struct {
+	client      *http.Client
+	middlewares []Middleware
+	userAgent   string
+	timeout     time.Duration
+	logger      Logger
+	telemetry   *telemetry.Provider
+}
+
+// Send requests with the client instead of the one of the configurations.
+// The client is not modified, and the cache and the CA bundle of the configurations are not applied to it.
+func WithHTTPClient(client *http.Client) Option {
+	return func(o *options) {
+		o.client = client
+	}
+}
+
+// Wrap the transport to the server with the middlewares, where the first one is the outermost.
+// They are under the cache, and see the Authorization header of the token pool.
+func WithMiddleware(middlewares ...Middleware) Option {
+	return func(o *options) {
+		o.middlewares = append(o.middlewares, middlewares...)
+	}
+}
+
+// Set the User-Agent header of requests.
+func WithUserAgent(userAgent string) Option {
+	return func(o *options) {
+		o.userAgent = userAgent
+	}
+}
+
+// Set the time limit of each request including retries of the token pool, zero means no limit.
+func WithTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.timeout = timeout
+	}
+}
+
+// Log requests with the logger.
+func WithLogger(logger Logger) Option {
+	return func(o *options) {
+		o.logger = logger
+	}
+}
+
+// Returns the options with the defaults.
+func newOptions(opts []Option) options {
+
+	o := options{userAgent: DefaultUserAgent}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// Returns the transport wrapped by the middlewares.
+func (o options) wrap(next http.RoundTripper) http.RoundTripper {
+
+	for i := len(o.middlewares) - 1; i >= 0; i-- {
+		next = o.middlewares[i](next)
+	}
+	return next
+}
+
+// RoundTripper which sets the User-Agent header.
+type userAgentTransport struct {
+	userAgent string
+	next      http.RoundTripper
+}
+
+func (t *userAgentTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+
+	// RoundTrippers must not modify the request.
+	r := req.Clone(req.Context())
+	r.Header.Set("User-Agent", t.userAgent)
+	return t.next.RoundTrip(r)
+}
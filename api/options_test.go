@@ -0,0 +1,144 @@
+package api_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kokoichi206/go-git-stats/api"
+	"github.com/kokoichi206/go-git-stats/util"
+	"github.com/stretchr/testify/require"
+)
+
+// RoundTripper of a function.
+type roundTripFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// Test double of the transport which records requests.
+type recordTransport struct {
+	requested []string
+}
+
+func (t *recordTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.requested = append(t.requested, req.URL.String())
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{},
+		Body:       ioutil.NopCloser(strings.NewReader(`{"Go": 100}`)),
+		Request:    req,
+	}, nil
+}
+
+func TestNewWithHTTPClient(t *testing.T) {
+
+	// Arrange
+	transport := &recordTransport{}
+	client := &http.Client{Transport: transport}
+
+	a := api.New(util.Config{ApiBaseURL: "https://ghe.example.com/api/v3"}, api.WithHTTPClient(client))
+
+	// Act
+	languages, err := a.Languages("kokoichi206/go-git-stats")
+
+	// Assert
+	require.NoError(t, err)
+	require.Equal(t, map[string]int{"Go": 100}, languages)
+	require.Equal(t, []string{"https://ghe.example.com/api/v3/repos/kokoichi206/go-git-stats/languages"}, transport.requested)
+	// The given client is not modified.
+	require.Same(t, transport, client.Transport)
+}
+
+func TestNewWithMiddlewares(t *testing.T) {
+
+	// Arrange
+	var userAgent string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userAgent = r.Header.Get("User-Agent")
+		w.Write([]byte(`{"Go": 100}`))
+	}))
+	defer ts.Close()
+
+	var mutex sync.Mutex
+	var calls []string
+	middleware := func(name string) api.Middleware {
+		return func(next http.RoundTripper) http.RoundTripper {
+			return roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				mutex.Lock()
+				calls = append(calls, name+" "+req.Header.Get("Authorization"))
+				mutex.Unlock()
+				return next.RoundTrip(req)
+			})
+		}
+	}
+
+	config := util.Config{ApiBaseURL: ts.URL, Token: poolTokenA, Tokens: []string{poolTokenB}}
+	a := api.New(config, api.WithMiddleware(middleware("first"), middleware("second")), api.WithUserAgent("ggs/test"))
+
+	// Act
+	_, err := a.Languages("kokoichi206/go-git-stats")
+
+	// Assert
+	require.NoError(t, err)
+	// Middlewares see the token selected by the token pool.
+	require.Equal(t, []string{"first token " + poolTokenA, "second token " + poolTokenA}, calls)
+	require.Equal(t, "ggs/test", userAgent)
+
+	// Default User-Agent
+	_, err = api.New(util.Config{ApiBaseURL: ts.URL}).Languages("kokoichi206/go-git-stats")
+	require.NoError(t, err)
+	require.Equal(t, api.DefaultUserAgent, userAgent)
+}
+
+func TestNewWithTimeout(t *testing.T) {
+
+	// Arrange
+	done := make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer ts.Close()
+	defer close(done)
+
+	a := api.New(util.Config{ApiBaseURL: ts.URL}, api.WithTimeout(20*time.Millisecond))
+
+	// Act
+	_, err := a.Languages("kokoichi206/go-git-stats")
+
+	// Assert
+	require.Error(t, err)
+}
+
+func TestNewHTTPClient(t *testing.T) {
+
+	// Arrange
+	var userAgent string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userAgent = r.Header.Get("User-Agent")
+		w.Write([]byte(`[]`))
+	}))
+	defer ts.Close()
+
+	var log bytes.Buffer
+	client := api.NewHTTPClient(util.Config{}, api.WithUserAgent("ggs/test"), api.WithLogger(api.NewTextLogger(&log)))
+
+	// Act
+	resp, err := client.Get(ts.URL + "/api/v4/projects")
+
+	// Assert
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, "ggs/test", userAgent)
+	require.Contains(t, log.String(), "GET "+ts.URL+"/api/v4/projects 200")
+}
@@ -0,0 +1,213 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Wait after the rate limit is exceeded when the reset time is unknown (or has passed by the clock skew).
+const defaultResetWait = time.Minute
+
+// Usage of a token of the token pool.
+type TokenUsage struct {
+	// Token whose middle is masked (e.g. ghp_***B4a).
+	Token string
+	// Requests sent with the token.
+	Requests int
+	// Remaining requests and when they are reset as of the last response, Remaining is -1 if unknown.
+	Remaining int
+	Reset     time.Time
+}
+
+// Optional interface of ApiCaller which reports the usage of the tokens of the token pool.
+type TokenUsageReporter interface {
+	TokenUsage() []TokenUsage
+}
+
+// Returns the usage of the tokens, nil without the token pool.
+func (a *Api) TokenUsage() []TokenUsage {
+
+	if a.pool == nil {
+		return nil
+	}
+	return a.pool.usage()
+}
+
+// Token of the token pool with its rate limit.
+type pooledToken struct {
+	token    string
+	requests int
+	// -1 means unknown (not used yet, or reset).
+	remaining int
+	reset     time.Time
+}
+
+// Tokens which share requests.
+// Each request is sent with the token which has the most remaining requests,
+// and requests wait only when the rate limits of all tokens are exceeded.
+type tokenPool struct {
+	mutex  *sync.Mutex
+	tokens []*pooledToken
+	next   http.RoundTripper
+	now    func() time.Time
+	after  func(time.Duration) <-chan time.Time
+}
+
+func newTokenPool(tokens []string) *tokenPool {
+
+	p := &tokenPool{
+		mutex: &sync.Mutex{},
+		now:   time.Now,
+		after: time.After,
+	}
+	for _, token := range tokens {
+		p.tokens = append(p.tokens, &pooledToken{token: token, remaining: -1})
+	}
+
+	return p
+}
+
+// Wrap the transport to the server, which is passed to util.Config.HTTPClientWith.
+func (p *tokenPool) transport(next http.RoundTripper) http.RoundTripper {
+	p.next = next
+	return p
+}
+
+// Send the request with the token which has the most remaining requests.
+// The Authorization header of the request is replaced,
+// and the request is sent again with another token if the rate limit is exceeded.
+func (p *tokenPool) RoundTrip(req *http.Request) (*http.Response, error) {
+
+	for {
+		t, err := p.acquire(req)
+		if err != nil {
+			return nil, err
+		}
+
+		r := req.Clone(req.Context())
+		r.Header.Set("Authorization", "token "+t.token)
+		if req.Body != nil && req.GetBody != nil {
+			if r.Body, err = req.GetBody(); err != nil {
+				return nil, err
+			}
+		}
+
+		resp, err := p.next.RoundTrip(r)
+		if err != nil {
+			return nil, err
+		}
+
+		if !p.update(t, resp) || (req.Body != nil && req.GetBody == nil) {
+			// The body of the request can not be sent again.
+			return resp, nil
+		}
+		resp.Body.Close()
+	}
+}
+
+// Returns the token which has the most remaining requests (unknown ones first).
+// It waits until the earliest reset if the rate limits of all tokens are exceeded.
+func (p *tokenPool) acquire(req *http.Request) (*pooledToken, error) {
+
+	for {
+		p.mutex.Lock()
+		now := p.now()
+		var best *pooledToken
+		earliest := time.Time{}
+		for _, t := range p.tokens {
+			if t.remaining == 0 && !now.Before(t.reset) {
+				t.remaining = -1
+			}
+			if t.remaining == 0 {
+				if earliest.IsZero() || t.reset.Before(earliest) {
+					earliest = t.reset
+				}
+				continue
+			}
+			if best == nil || best.remaining != -1 && (t.remaining == -1 || t.remaining > best.remaining) {
+				best = t
+			}
+		}
+		if best != nil {
+			best.requests += 1
+			if best.remaining > 0 {
+				// Concurrent requests go to other tokens before the response.
+				best.remaining -= 1
+			}
+			p.mutex.Unlock()
+			return best, nil
+		}
+		p.mutex.Unlock()
+
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-p.after(earliest.Sub(now)):
+		}
+	}
+}
+
+// Update the rate limit of the token by the response,
+// and returns whether the request failed since the rate limit is exceeded.
+func (p *tokenPool) update(t *pooledToken, resp *http.Response) bool {
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	remaining, err := strconv.Atoi(resp.Header.Get("X-RateLimit-Remaining"))
+	if err != nil {
+		// GitHub Enterprise Server without the rate limit
+		return false
+	}
+	t.remaining = remaining
+	if reset, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+		t.reset = time.Unix(reset, 0)
+	}
+
+	exceeded := remaining == 0 && (resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusTooManyRequests)
+	if exceeded && !t.reset.After(p.now()) {
+		t.reset = p.now().Add(defaultResetWait)
+	}
+
+	return exceeded
+}
+
+// Returns the usage of the tokens in the order of the configurations.
+func (p *tokenPool) usage() []TokenUsage {
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	usage := make([]TokenUsage, 0, len(p.tokens))
+	for _, t := range p.tokens {
+		usage = append(usage, TokenUsage{
+			Token:     maskToken(t.token),
+			Requests:  t.requests,
+			Remaining: t.remaining,
+			Reset:     t.reset,
+		})
+	}
+
+	return usage
+}
+
+// Returns the token whose middle is masked to tell tokens apart.
+func maskToken(token string) string {
+
+	prefix := ""
+	for _, p := range []string{"github_pat_", "ghp_", "gho_", "ghu_", "ghs_"} {
+		if len(token) > len(p) && token[:len(p)] == p {
+			prefix = p
+			break
+		}
+	}
+
+	suffix := ""
+	if len(token)-len(prefix) >= 16 {
+		suffix = token[len(token)-4:]
+	}
+
+	return prefix + "***" + suffix
+}
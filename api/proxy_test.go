@@ -0,0 +1,171 @@
+package api_test
+
+import (
+	"encoding/pem"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/kokoichi206/go-git-stats/api"
+	"github.com/kokoichi206/go-git-stats/util"
+	"github.com/stretchr/testify/require"
+)
+
+// HTTPS proxy which tunnels CONNECT requests and records their hosts.
+type connectProxy struct {
+	mutex sync.Mutex
+	hosts []string
+}
+
+func (p *connectProxy) start() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodConnect {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		p.mutex.Lock()
+		p.hosts = append(p.hosts, r.Host)
+		p.mutex.Unlock()
+
+		target, err := net.Dial("tcp", r.Host)
+		if err != nil {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			target.Close()
+			return
+		}
+		conn.Write([]byte("HTTP/1.1 200 Connection Established\r\n\r\n"))
+		go func() {
+			io.Copy(target, conn)
+			target.Close()
+		}()
+		io.Copy(conn, target)
+		conn.Close()
+	}))
+}
+
+func (p *connectProxy) tunneled() []string {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return append([]string(nil), p.hosts...)
+}
+
+// GitHub API over TLS with its self-signed certificate.
+func newTLSServer(t *testing.T) (*httptest.Server, string) {
+
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/rate_limit":
+			w.Header().Set("X-RateLimit-Remaining", "4990")
+			w.Write([]byte(`{}`))
+		case "/user":
+			w.Write([]byte(`{"login": "kokoichi206"}`))
+		default:
+			w.Write([]byte(`{"Go": 100}`))
+		}
+	}))
+
+	path := filepath.Join(t.TempDir(), "ca.pem")
+	certificate := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ts.Certificate().Raw})
+	require.NoError(t, ioutil.WriteFile(path, certificate, 0600))
+
+	return ts, path
+}
+
+func TestProxy(t *testing.T) {
+
+	testCases := []struct {
+		name string
+		// Modify the configurations with the URLs of the proxy and the server.
+		setup     func(t *testing.T, config *util.Config, proxyURL, serverHost, caPath string)
+		assertion func(t *testing.T, err error, tunneled []string, serverHost string)
+	}{
+		{
+			name: "OK through the proxy with the CA",
+			setup: func(t *testing.T, config *util.Config, proxyURL, serverHost, caPath string) {
+				config.Proxy = proxyURL
+				require.NoError(t, config.SetCACert(caPath))
+			},
+			assertion: func(t *testing.T, err error, tunneled []string, serverHost string) {
+				require.NoError(t, err)
+				// Connections are reused by the methods.
+				require.NotEmpty(t, tunneled)
+				for _, host := range tunneled {
+					require.Equal(t, serverHost, host)
+				}
+			},
+		},
+		{
+			name: "OK through the proxy without verification",
+			setup: func(t *testing.T, config *util.Config, proxyURL, serverHost, caPath string) {
+				config.Proxy = strings.TrimPrefix(proxyURL, "http://")
+				config.InsecureSkipVerify = true
+			},
+			assertion: func(t *testing.T, err error, tunneled []string, serverHost string) {
+				require.NoError(t, err)
+				require.NotEmpty(t, tunneled)
+			},
+		},
+		{
+			name: "OK without the proxy for NO_PROXY",
+			setup: func(t *testing.T, config *util.Config, proxyURL, serverHost, caPath string) {
+				config.Proxy = proxyURL
+				config.NoProxy = "example.com, 127.0.0.0/8"
+				require.NoError(t, config.SetCACert(caPath))
+			},
+			assertion: func(t *testing.T, err error, tunneled []string, serverHost string) {
+				require.NoError(t, err)
+				require.Empty(t, tunneled)
+			},
+		},
+		{
+			name: "Error without the CA",
+			setup: func(t *testing.T, config *util.Config, proxyURL, serverHost, caPath string) {
+				config.Proxy = proxyURL
+			},
+			assertion: func(t *testing.T, err error, tunneled []string, serverHost string) {
+				require.Error(t, err)
+				require.NotEmpty(t, tunneled)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			ts, caPath := newTLSServer(t)
+			defer ts.Close()
+			p := &connectProxy{}
+			proxy := p.start()
+			defer proxy.Close()
+			serverHost := strings.TrimPrefix(ts.URL, "https://")
+
+			config := util.Config{ApiBaseURL: ts.URL, Token: logToken}
+			tc.setup(t, &config, proxy.URL, serverHost, caPath)
+			a := api.ExportNewApi(config)
+
+			// Act
+			_, err := a.RateLimit()
+			if err == nil {
+				_, err = a.CheckToken()
+			}
+			if err == nil {
+				_, err = a.Languages("kokoichi206/go-git-stats")
+			}
+
+			// Assert
+			tc.assertion(t, err, p.tunneled(), serverHost)
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/kokoichi206/go-git-stats/api/internal/rest"
+)
+
+// Rate limit of the REST API for the access token (or the client address without tokens).
+type RateLimit struct {
+	// Whether the rate limit is enabled, which may be disabled on GitHub Enterprise Server.
+	Enabled   bool
+	Limit     int
+	Remaining int
+	// When the remaining requests are reset.
+	Reset time.Time
+	// Time of the server (Date header), which is zero if unknown.
+	ServerTime time.Time
+}
+
+// Optional interface of ApiCaller which gets the rate limit.
+type RateLimitChecker interface {
+	RateLimit() (RateLimit, error)
+}
+
+// Get the rate limit of the REST API.
+// Getting the rate limit does not count against it.
+// See documentation:
+// https://docs.github.com/en/rest/rate-limit#get-rate-limit-status-for-the-authenticated-user
+func (a *Api) RateLimit() (RateLimit, error) {
+
+	URL := fmt.Sprintf("%s/rate_limit", a.config.ApiBaseURL)
+
+	req, err := newRequest("GET", URL)
+	if err != nil {
+		return RateLimit{}, fmt.Errorf("failed to http.NewRequest: %w", err)
+	}
+
+	// Set request header.
+	req.Header.Add("Accept", "application/vnd.github+json")
+	// The rate limit changes every request.
+	req.Header.Add("Cache-Control", "no-cache")
+	if err := a.setAuthorization(req); err != nil {
+		return RateLimit{}, err
+	}
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return RateLimit{}, fmt.Errorf("failed to client.Do: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var rateLimit RateLimit
+	if date, err := http.ParseTime(resp.Header.Get("Date")); err == nil {
+		rateLimit.ServerTime = date
+	}
+
+	// GitHub Enterprise Server without the rate limit
+	if resp.StatusCode == http.StatusNotFound {
+		return rateLimit, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return RateLimit{}, rest.NewStatusError(resp)
+	}
+
+	rateLimit.Enabled = true
+	rateLimit.Limit, _ = strconv.Atoi(resp.Header.Get("X-RateLimit-Limit"))
+	rateLimit.Remaining, _ = strconv.Atoi(resp.Header.Get("X-RateLimit-Remaining"))
+	if reset, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+		rateLimit.Reset = time.Unix(reset, 0)
+	}
+
+	return rateLimit, nil
+}
@@ -3,8 +3,9 @@ package api
 import (
 	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
+
+	"github.com/kokoichi206/go-git-stats/api/internal/rest"
 )
 
 // Lists public repositories for a user.
@@ -12,56 +13,35 @@ import (
 // https://docs.github.com/ja/rest/repos/repos#list-public-repositories
 func (a *Api) ListPublicRepositories(userName string) ([]Repository, error) {
 
-	var (
-		URL     = fmt.Sprintf("%s/users/%s/repos?per_page=100", a.config.ApiBaseURL, userName)
-		retries = 3
-	)
-
-	client := &http.Client{}
-	req, err := http.NewRequest("GET", URL, nil)
+	URL := fmt.Sprintf("%s/users/%s/repos?per_page=100", a.config.ApiBaseURL, userName)
+	body, err := a.get(URL, false)
 	if err != nil {
-		return nil, fmt.Errorf("failed to http.NewRequest: %w", err)
+		return nil, err
 	}
 
-	// Set request header.
-	req.Header.Add("Accept", "application/vnd.github+json")
-
-	var resp *http.Response
-	success := false
-	for retries > 0 {
-		// > If the returned error is nil, the Response will contain a non-nil
-		// > Body which the user is expected to close.
-		resp, err = client.Do(req)
-
-		if err != nil {
-			// Invalid URL (Like different scheme) etc.
-			retries -= 1
-			continue
-		}
-
-		if resp.StatusCode == http.StatusOK {
-			// Success!
-			success = true
-			break
-		}
+	var repositories []Repository
+	if err := json.Unmarshal(body, &repositories); err != nil {
+		return nil, fmt.Errorf("failed to json.Unmarshal: %w", err)
+	}
 
-		if resp.StatusCode/100 == 4 {
-			// If the StatusCode starts with 4, it is user's error,
-			// so it should not be retried.
-			return nil, fmt.Errorf("failed to client.Do: StatusCode is %d", resp.StatusCode)
-		}
+	return repositories, nil
+}
 
-		retries -= 1
-	}
+// Lists repositories for the authenticated user.
+// Config must have the github access token.
+// See documentation:
+// https://docs.github.com/ja/rest/repos/repos#list-repositories-for-the-authenticated-user
+func (a *Api) ListRepositoriesForAuthenticatedUser() ([]Repository, error) {
 
-	if !success {
-		return nil, fmt.Errorf("failed to client.Do after several retries.")
+	// GitHub App installations are not users.
+	if a.app != nil {
+		return a.listInstallationRepositories()
 	}
 
-	body, err := io.ReadAll(resp.Body)
-	defer resp.Body.Close()
+	URL := fmt.Sprintf("%s/user/repos?per_page=100", a.config.ApiBaseURL)
+	body, err := a.get(URL, true)
 	if err != nil {
-		return nil, fmt.Errorf("failed to io.ReadAll: %w", err)
+		return nil, err
 	}
 
 	var repositories []Repository
@@ -72,69 +52,60 @@ func (a *Api) ListPublicRepositories(userName string) ([]Repository, error) {
 	return repositories, nil
 }
 
-// Lists repositories for the authenticated user.
-// Config must have the github access token.
+// Lists repositories that the GitHub App installation can access.
+// Pages are read until all repositories of total_count are listed.
 // See documentation:
-// https://docs.github.com/ja/rest/repos/repos#list-repositories-for-the-authenticated-user
-func (a *Api) ListRepositoriesForAuthenticatedUser() ([]Repository, error) {
+// https://docs.github.com/en/rest/apps/installations#list-repositories-accessible-to-the-app-installation
+func (a *Api) listInstallationRepositories() ([]Repository, error) {
 
-	var (
-		URL     = fmt.Sprintf("%s/user/repos?per_page=100", a.config.ApiBaseURL)
-		retries = 3
-	)
-
-	client := &http.Client{}
-	req, err := http.NewRequest("GET", URL, nil)
+	token, _, err := a.app.get()
 	if err != nil {
-		return nil, fmt.Errorf("failed to http.NewRequest: %w", err)
+		return nil, err
 	}
 
-	// Set request header.
-	req.Header.Add("Accept", "application/vnd.github+json")
-	req.Header.Add("Authorization", fmt.Sprintf("token %s", a.config.Token))
-
-	var resp *http.Response
-	success := false
-	for retries > 0 {
-		// > If the returned error is nil, the Response will contain a non-nil
-		// > Body which the user is expected to close.
-		resp, err = client.Do(req)
+	header := http.Header{}
+	header.Add("Accept", "application/vnd.github+json")
+	header.Add("Authorization", fmt.Sprintf("token %s", token))
 
+	var repositories []Repository
+	for page := 1; ; page++ {
+		URL := fmt.Sprintf("%s/installation/repositories?per_page=100&page=%d", a.config.ApiBaseURL, page)
+		body, _, err := rest.Get(a.client, URL, header)
 		if err != nil {
-			// Invalid URL (Like different scheme) etc.
-			retries -= 1
-			continue
+			return nil, err
 		}
 
-		if resp.StatusCode == http.StatusOK {
-			// Success!
-			success = true
-			break
+		var installation struct {
+			TotalCount   int          `json:"total_count"`
+			Repositories []Repository `json:"repositories"`
 		}
-
-		if resp.StatusCode/100 == 4 {
-			// If the StatusCode starts with 4, it is user's error,
-			// so it should not be retried.
-			return nil, fmt.Errorf("failed to client.Do: StatusCode is %d", resp.StatusCode)
+		if err := json.Unmarshal(body, &installation); err != nil {
+			return nil, fmt.Errorf("failed to json.Unmarshal: %w", err)
 		}
+		repositories = append(repositories, installation.Repositories...)
 
-		retries -= 1
+		if len(installation.Repositories) == 0 || len(repositories) >= installation.TotalCount {
+			return repositories, nil
+		}
 	}
+}
 
-	if !success {
-		return nil, fmt.Errorf("failed to client.Do after several retries.")
-	}
+// Lists languages for the specified repository.
+// The value shown for each language is the number of bytes of code written in that language.
+// See documentation:
+// https://docs.github.com/ja/rest/repos/repos#list-repository-languages
+func (a *Api) Languages(fullName string) (map[string]int, error) {
 
-	body, err := io.ReadAll(resp.Body)
-	defer resp.Body.Close()
+	URL := fmt.Sprintf("%s/repos/%s/languages", a.config.ApiBaseURL, fullName)
+	body, err := a.get(URL, true)
 	if err != nil {
-		return nil, fmt.Errorf("failed to io.ReadAll: %w", err)
+		return nil, err
 	}
 
-	var repositories []Repository
-	if err := json.Unmarshal(body, &repositories); err != nil {
+	var languages map[string]int
+	if err := json.Unmarshal(body, &languages); err != nil {
 		return nil, fmt.Errorf("failed to json.Unmarshal: %w", err)
 	}
 
-	return repositories, nil
+	return languages, nil
 }
@@ -1,9 +1,12 @@
 package api_test
 
 import (
+	"encoding/pem"
 	"fmt"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -49,12 +52,14 @@ func TestListPublicRepositories(t *testing.T) {
 				require.Equal(t, 5, len(repositories))
 
 				expectedRepoNames := []string{"account-book-api", "account-book-ios", "action-URL-watcher", "actions-diff-and-notify", "ai_web_app_flask"}
+				expectedLanguages := []string{"Go", "Swift", "", "Kotlin", "Python"}
 				for i := 0; i < 5; i++ {
 					repository := repositories[i]
 					repo := expectedRepoNames[i]
 					require.False(t, repository.Private)
 					require.Equal(t, repo, repository.Name)
 					require.Equal(t, fmt.Sprintf("kokoichi206/%s", repo), repository.FullName)
+					require.Equal(t, expectedLanguages[i], repository.Language)
 				}
 				t.Log(ts.url)
 				require.Equal(t, "/users/kokoichi206/repos", ts.url.Path)
@@ -385,3 +390,144 @@ func TestListRepositoriesForAuthenticatedUser(t *testing.T) {
 		})
 	}
 }
+
+func TestLanguages(t *testing.T) {
+
+	s := httptest.NewServer(nil)
+	defer s.Close()
+
+	ts := TestServer{
+		server:    s,
+		header:    nil,
+		apiCalled: 0,
+	}
+
+	config := util.Config{
+		ApiBaseURL: ts.server.URL,
+		Token:      "ghq_kokoichi206token",
+	}
+	a := api.ExportNewApi(config)
+
+	testCases := []struct {
+		name      string
+		fullName  string
+		setup     func(testServer *httptest.Server)
+		assertion func(t *testing.T, err error, languages map[string]int)
+		tearDown  func()
+	}{
+		{
+			name:     "OK",
+			fullName: "kokoichi206/go-git-stats",
+			setup: func(testServer *httptest.Server) {
+				ts.server.Config.Handler = ts.NewRouter(http.StatusOK, mockLanguages)
+			},
+			assertion: func(t *testing.T, err error, languages map[string]int) {
+
+				require.NoError(t, err)
+				t.Log(languages)
+				require.Equal(t, map[string]int{"Go": 40500, "Shell": 1520, "Makefile": 380}, languages)
+
+				require.Equal(t, "/repos/kokoichi206/go-git-stats/languages", ts.url.Path)
+				require.Equal(t, "application/vnd.github+json", ts.header.Get("Accept"))
+				require.Equal(t, "token ghq_kokoichi206token", ts.header.Get("Authorization"))
+
+				// Api was called only once
+				require.Equal(t, 1, ts.apiCalled)
+			},
+			tearDown: func() {
+			},
+		},
+		{
+			name:     "Error Not Found",
+			fullName: "kokoichi206/not-found",
+			setup: func(testServer *httptest.Server) {
+				ts.server.Config.Handler = ts.NewRouter(http.StatusNotFound, "")
+			},
+			assertion: func(t *testing.T, err error, languages map[string]int) {
+
+				require.Error(t, err)
+				require.True(t, strings.Contains(err.Error(), "client.Do"))
+				require.Nil(t, languages)
+
+				// Api was called only once
+				require.Equal(t, 1, ts.apiCalled)
+			},
+			tearDown: func() {
+			},
+		},
+		{
+			name:     "Error after retry",
+			fullName: "kokoichi206/go-git-stats",
+			setup: func(testServer *httptest.Server) {
+				ts.server.Config.Handler = ts.NewRouter(http.StatusInternalServerError, "")
+			},
+			assertion: func(t *testing.T, err error, languages map[string]int) {
+
+				require.Error(t, err)
+				require.Equal(t, "failed to client.Do after several retries.", err.Error())
+
+				// Api was called 3 times! (and failed...)
+				require.Equal(t, 3, ts.apiCalled)
+			},
+			tearDown: func() {
+			},
+		},
+		{
+			name:     "Unmarshal failed with incomplete data",
+			fullName: "kokoichi206/go-git-stats",
+			setup: func(testServer *httptest.Server) {
+				ts.server.Config.Handler = ts.NewRouter(http.StatusOK, `{"Go": "40500"}`)
+			},
+			assertion: func(t *testing.T, err error, languages map[string]int) {
+
+				require.Error(t, err)
+				require.True(t, strings.Contains(err.Error(), "json.Unmarshal"))
+				require.Nil(t, languages)
+			},
+			tearDown: func() {
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			tc.setup(ts.server)
+			defer tc.tearDown()
+			defer ts.init()
+
+			// Act
+			languages, err := a.Languages(tc.fullName)
+
+			// Assert
+			tc.assertion(t, err, languages)
+		})
+	}
+}
+
+func TestListPublicRepositoriesEnterpriseServer(t *testing.T) {
+
+	ts := TestServer{}
+	s := httptest.NewTLSServer(ts.NewRouter(http.StatusOK, mockRepositories))
+	defer s.Close()
+
+	// CA bundle of the self-signed certificate of the test server.
+	path := filepath.Join(t.TempDir(), "ca.pem")
+	certificate := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: s.Certificate().Raw})
+	require.NoError(t, ioutil.WriteFile(path, certificate, 0600))
+
+	config := util.Config{}
+	require.NoError(t, config.SetHost(s.URL))
+	require.NoError(t, config.SetCACert(path))
+	a := api.New(config)
+
+	// Act
+	repositories, err := a.ListPublicRepositories("kokoichi206")
+
+	// Assert
+	require.NoError(t, err)
+	require.Equal(t, 5, len(repositories))
+	require.Equal(t, "/api/v3/users/kokoichi206/repos", ts.url.Path)
+}
@@ -197,3 +197,120 @@ func TestWeeklyCommitActivity(t *testing.T) {
 		})
 	}
 }
+
+func TestParticipation(t *testing.T) {
+
+	s := httptest.NewServer(nil)
+	defer s.Close()
+
+	ts := TestServer{
+		server: s,
+		header: nil,
+	}
+
+	config := util.Config{
+		ApiBaseURL: ts.server.URL,
+		Token:      "ghq_kokoichi206token",
+	}
+	a := api.ExportNewApi(config)
+
+	testCases := []struct {
+		name      string
+		fullName  string
+		setup     func(testServer *httptest.Server)
+		assertion func(t *testing.T, err error, participation api.Participation)
+		tearDown  func()
+	}{
+		{
+			name:     "OK",
+			fullName: "kokoichi206/go-git-stats",
+			setup: func(testServer *httptest.Server) {
+				ts.server.Config.Handler = ts.NewRouter(http.StatusOK, mockParticipation)
+			},
+			assertion: func(t *testing.T, err error, participation api.Participation) {
+
+				require.NoError(t, err)
+				t.Log(participation)
+				require.Equal(t, []int{11, 21, 15, 2}, participation.All)
+				require.Equal(t, []int{3, 2, 3, 0}, participation.Owner)
+
+				// Assert header
+				require.Equal(t, "application/vnd.github+json", ts.header.Get("Accept"))
+				require.Equal(t, "token ghq_kokoichi206token", ts.header.Get("Authorization"))
+				// Assert URL
+				require.Equal(t, "/repos/kokoichi206/go-git-stats/stats/participation", ts.url.Path)
+
+				// Api was called only once
+				require.Equal(t, 1, ts.apiCalled)
+			},
+			tearDown: func() {
+			},
+		},
+		{
+			name:     "Error Not Found",
+			fullName: "notFoundUser",
+			setup: func(testServer *httptest.Server) {
+				ts.server.Config.Handler = ts.NewRouter(http.StatusNotFound, "")
+			},
+			assertion: func(t *testing.T, err error, participation api.Participation) {
+
+				require.Error(t, err)
+				require.True(t, strings.Contains(err.Error(), "client.Do"))
+				require.Nil(t, participation.All)
+
+				// Api was called only once
+				require.Equal(t, 1, ts.apiCalled)
+			},
+			tearDown: func() {
+			},
+		},
+		{
+			name:     "Error after retry",
+			fullName: "kokoichi206/go-git-stats",
+			setup: func(testServer *httptest.Server) {
+				ts.server.Config.Handler = ts.NewRouter(http.StatusInternalServerError, "")
+			},
+			assertion: func(t *testing.T, err error, participation api.Participation) {
+
+				require.Error(t, err)
+				require.Equal(t, "failed to client.Do after several retries.", err.Error())
+
+				// Api was called 3 times! (and failed...)
+				require.Equal(t, 3, ts.apiCalled)
+			},
+			tearDown: func() {
+			},
+		},
+		{
+			name:     "Unmarshal failed with incomplete data",
+			fullName: "kokoichi206/go-git-stats",
+			setup: func(testServer *httptest.Server) {
+				ts.server.Config.Handler = ts.NewRouter(http.StatusOK, `{"all": ["1"]}`)
+			},
+			assertion: func(t *testing.T, err error, participation api.Participation) {
+
+				require.Error(t, err)
+				require.True(t, strings.Contains(err.Error(), "json.Unmarshal"))
+			},
+			tearDown: func() {
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			tc.setup(ts.server)
+			defer tc.tearDown()
+			defer ts.init()
+
+			// Act
+			participation, err := a.Participation(tc.fullName)
+
+			// Assert
+			tc.assertion(t, err, participation)
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/kokoichi206/go-git-stats/api/internal/rest"
+	"github.com/kokoichi206/go-git-stats/telemetry"
+	"github.com/kokoichi206/go-git-stats/util"
+)
+
+// Record a span and metrics of each request with the provider.
+func WithTelemetry(provider *telemetry.Provider) Option {
+	return func(o *options) {
+		o.telemetry = provider
+	}
+}
+
+// RoundTripper which records spans and metrics of requests:
+// requests, retries, cache hits and latencies.
+type telemetryTransport struct {
+	provider  *telemetry.Provider
+	requests  *telemetry.Counter
+	retries   *telemetry.Counter
+	cacheHits *telemetry.Counter
+	duration  *telemetry.Histogram
+	next      http.RoundTripper
+	now       func() time.Time
+}
+
+func newTelemetryTransport(provider *telemetry.Provider, next http.RoundTripper) *telemetryTransport {
+	return &telemetryTransport{
+		provider:  provider,
+		requests:  provider.Counter("ggs.api.requests", "Requests to the API.", "{request}"),
+		retries:   provider.Counter("ggs.api.retries", "Requests which are sent again after failures.", "{request}"),
+		cacheHits: provider.Counter("ggs.api.cache_hits", "Responses from the cache.", "{request}"),
+		duration:  provider.Histogram("ggs.api.duration", "Latency of requests.", "ms", telemetry.DefaultLatencyBounds),
+		next:      next,
+		now:       time.Now,
+	}
+}
+
+func (t *telemetryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+
+	// Counted by the logTransport above.
+	attempt := rest.Attempt(req)
+
+	ctx, span := t.provider.Start(req.Context(), req.Method, telemetry.SpanKindClient,
+		telemetry.String("http.method", req.Method),
+		telemetry.String("http.url", util.Redact(req.URL.String())),
+		telemetry.Int("ggs.attempt", attempt),
+	)
+	defer span.End()
+
+	start := t.now()
+	resp, err := t.next.RoundTrip(req.WithContext(ctx))
+	latency := float64(t.now().Sub(start).Microseconds()) / 1000
+
+	status := 0
+	cacheHit := false
+	if err != nil {
+		span.RecordError(err)
+	} else {
+		status = resp.StatusCode
+		cacheHit = resp.Header.Get(util.CacheHeader) == "hit"
+		span.SetAttributes(telemetry.Int("http.status_code", status), telemetry.Bool("ggs.cache_hit", cacheHit))
+		if remaining, err := strconv.Atoi(resp.Header.Get("X-RateLimit-Remaining")); err == nil {
+			span.SetAttributes(telemetry.Int("ggs.rate_limit.remaining", remaining))
+		}
+		if status >= http.StatusBadRequest {
+			span.RecordError(&StatusError{StatusCode: status, URL: req.URL.String()})
+		}
+	}
+
+	method := telemetry.String("http.method", req.Method)
+	t.requests.Add(1, method, telemetry.Int("http.status_code", status), telemetry.Bool("ggs.cache_hit", cacheHit))
+	t.duration.Record(latency, method, telemetry.Bool("ggs.cache_hit", cacheHit))
+	if attempt > 1 {
+		t.retries.Add(1, method)
+	}
+	if cacheHit {
+		t.cacheHits.Add(1, method)
+	}
+
+	return resp, err
+}
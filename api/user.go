@@ -0,0 +1,98 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/kokoichi206/go-git-stats/api/internal/rest"
+	"github.com/kokoichi206/go-git-stats/util"
+)
+
+// Information about the access token.
+type TokenInfo struct {
+	Login string
+	// Type of the token by its format.
+	Type string
+	// Scopes of the classic (or OAuth) token, which are empty for fine-grained tokens.
+	Scopes []string
+	// Expiration of the token, which is empty if it never expires.
+	Expiration string
+}
+
+// Optional interface of ApiCaller which can check the access token online.
+type TokenChecker interface {
+	CheckToken() (TokenInfo, error)
+}
+
+// Check the access token with the authenticated user.
+// Scopes and the expiration are read from the response header.
+// See documentation:
+// https://docs.github.com/en/rest/users/users#get-the-authenticated-user
+// https://docs.github.com/en/apps/oauth-apps/building-oauth-apps/scopes-for-oauth-apps
+func (a *Api) CheckToken() (TokenInfo, error) {
+
+	// Installation tokens can not get the authenticated user.
+	if a.app != nil {
+		_, expiresAt, err := a.app.get()
+		if err != nil {
+			return TokenInfo{}, err
+		}
+		return TokenInfo{
+			Login:      fmt.Sprintf("installation %d of app %d", a.config.App.InstallationID, a.config.App.ID),
+			Type:       util.TokenTypeInstallation,
+			Expiration: expiresAt.UTC().Format("2006-01-02 15:04:05 UTC"),
+		}, nil
+	}
+
+	URL := fmt.Sprintf("%s/user", a.config.ApiBaseURL)
+
+	req, err := newRequest("GET", URL)
+	if err != nil {
+		return TokenInfo{}, fmt.Errorf("failed to http.NewRequest: %w", err)
+	}
+
+	// Set request header.
+	req.Header.Add("Accept", "application/vnd.github+json")
+	req.Header.Add("Authorization", fmt.Sprintf("token %s", a.config.Token))
+	// The token may be revoked since the last check.
+	req.Header.Add("Cache-Control", "no-cache")
+
+	// Checking is not retried.
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return TokenInfo{}, fmt.Errorf("failed to client.Do: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return TokenInfo{}, rest.NewStatusError(resp)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return TokenInfo{}, fmt.Errorf("failed to io.ReadAll: %w", err)
+	}
+
+	var user struct {
+		Login string `json:"login"`
+	}
+	if err := json.Unmarshal(body, &user); err != nil {
+		return TokenInfo{}, fmt.Errorf("failed to json.Unmarshal: %w", err)
+	}
+
+	info := TokenInfo{
+		Login:      user.Login,
+		Type:       util.TokenType(a.config.Token),
+		Expiration: resp.Header.Get("GitHub-Authentication-Token-Expiration"),
+	}
+	for _, scope := range strings.Split(resp.Header.Get("X-OAuth-Scopes"), ",") {
+		if scope = strings.TrimSpace(scope); scope != "" {
+			info.Scopes = append(info.Scopes, scope)
+		}
+	}
+
+	return info, nil
+}
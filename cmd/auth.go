@@ -0,0 +1,298 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/kokoichi206/go-git-stats/api"
+	"github.com/kokoichi206/go-git-stats/api/oauth"
+	"github.com/kokoichi206/go-git-stats/util"
+	"github.com/urfave/cli/v2"
+)
+
+// Scopes requested by 'auth login' (private repositories need 'repo').
+const defaultScopes = "repo"
+
+// Return cli command about authentication.
+func (c *Cmd) AuthCommand() *cli.Command {
+	return &cli.Command{
+		Name:        "auth",
+		Description: "Log in to GitHub, show the status, log out, and lock the stored credentials",
+		Subcommands: []*cli.Command{
+			{
+				Name:        "login",
+				Description: "Log in to the GitHub host with the OAuth device flow",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:    "client-id",
+						Usage:   "client ID of the OAuth app with the device flow enabled",
+						EnvVars: []string{"GGS_OAUTH_CLIENT_ID"},
+					},
+					&cli.StringFlag{Name: "scopes", Value: defaultScopes, Usage: "scopes separated by spaces"},
+				},
+				Action: c.login,
+			},
+			{
+				Name:        "status",
+				Description: "Show the user, the scopes and the expiration of the token",
+				Action:      c.authStatus,
+			},
+			{
+				Name:        "logout",
+				Description: "Remove the stored credential of the GitHub host",
+				Action:      c.logout,
+			},
+			{
+				Name:        "lock",
+				Description: "Encrypt the credentials file with a passphrase or a key file",
+				Flags:       []cli.Flag{keyFileFlag()},
+				Action:      c.lock,
+			},
+			{
+				Name:        "unlock",
+				Description: "Decrypt the credentials file and store the credentials in plaintext",
+				Flags:       []cli.Flag{keyFileFlag()},
+				Action:      c.unlock,
+			},
+		},
+	}
+}
+
+// Log in with the OAuth device flow and store the credential.
+func (c *Cmd) login(cc *cli.Context) error {
+
+	clientID := cc.String("client-id")
+	if clientID == "" {
+		return errors.New("client ID of the OAuth app is not given: use -client-id or [GGS_OAUTH_CLIENT_ID]")
+	}
+
+	webURL, err := util.GitHubWebURL(c.config.Host)
+	if err != nil {
+		return err
+	}
+
+	flow := oauth.NewDeviceFlow(webURL, clientID, c.config.HTTPClient())
+	code, err := flow.RequestCode(cc.String("scopes"))
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("First copy your one-time code: %s\n", code.UserCode)
+	fmt.Printf("Then open %s in your browser and enter the code.\n", code.VerificationURI)
+	fmt.Println("Waiting for authorization...")
+
+	token, err := flow.PollToken(code)
+	if err != nil {
+		return err
+	}
+
+	config := c.config
+	config.Token = token.AccessToken
+	info, err := api.New(config, c.options...).(api.TokenChecker).CheckToken()
+	if err != nil {
+		return err
+	}
+
+	path, err := util.CredentialsPath()
+	if err != nil {
+		return err
+	}
+	credentials, err := util.LoadCredentials(path)
+	if err != nil {
+		return err
+	}
+	credentials[util.HostName(c.config.Host)] = util.Credential{Token: token.AccessToken, User: info.Login}
+	if err := util.SaveCredentials(path, credentials); err != nil {
+		return err
+	}
+
+	fmt.Printf("Logged in to %s as %s\n", util.HostName(c.config.Host), info.Login)
+	return nil
+}
+
+// Show the status of the token of the GitHub host.
+func (c *Cmd) authStatus(cc *cli.Context) error {
+
+	host := util.HostName(c.config.Host)
+	if c.config.Token == "" && !c.config.App.Configured() {
+		return fmt.Errorf("not logged in to %s", host)
+	}
+
+	info, err := api.New(c.config, c.options...).(api.TokenChecker).CheckToken()
+	if err != nil {
+		return fmt.Errorf("the token of %s (%s) is not valid: %w", host, c.config.TokenSource, err)
+	}
+
+	PrintTokenInfo(os.Stdout, host, info)
+	fmt.Printf("Source:      %s\n", c.config.TokenSource)
+	return nil
+}
+
+// Remove the stored credential of the GitHub host.
+func (c *Cmd) logout(cc *cli.Context) error {
+
+	host := util.HostName(c.config.Host)
+
+	path, err := util.CredentialsPath()
+	if err != nil {
+		return err
+	}
+	credentials, err := util.LoadCredentials(path)
+	if err != nil {
+		return err
+	}
+
+	credential, ok := credentials[host]
+	if !ok {
+		return fmt.Errorf("not logged in to %s", host)
+	}
+	delete(credentials, host)
+	if err := util.SaveCredentials(path, credentials); err != nil {
+		return err
+	}
+
+	fmt.Printf("Logged out of %s (%s)\n", host, credential.User)
+	if c.config.TokenSource == util.TokenSourceEnv {
+		fmt.Println("GGS_TOKEN is still set and used.")
+	}
+	return nil
+}
+
+// Flag of the key file to encrypt the credentials file.
+func keyFileFlag() cli.Flag {
+	return &cli.StringFlag{
+		Name:    "key-file",
+		Usage:   "file of the secret (at least 16 bytes) instead of a passphrase",
+		EnvVars: []string{"GGS_KEY_FILE"},
+	}
+}
+
+// Encrypt the credentials file.
+func (c *Cmd) lock(cc *cli.Context) error {
+
+	path, err := util.CredentialsPath()
+	if err != nil {
+		return err
+	}
+
+	secret, err := readSecret(cc, true)
+	if err != nil {
+		return err
+	}
+
+	if err := util.LockCredentials(path, secret); err != nil {
+		return err
+	}
+
+	fmt.Printf("Locked %s\n", path)
+	if cc.String("key-file") != "" {
+		fmt.Println("Set the key file to GGS_KEY_FILE to use the stored tokens.")
+	} else {
+		fmt.Println("Set the passphrase to GGS_PASSPHRASE to use the stored tokens.")
+	}
+	return nil
+}
+
+// Decrypt the credentials file.
+func (c *Cmd) unlock(cc *cli.Context) error {
+
+	path, err := util.CredentialsPath()
+	if err != nil {
+		return err
+	}
+
+	secret, err := readSecret(cc, false)
+	if err != nil {
+		return err
+	}
+
+	if err := util.UnlockCredentials(path, secret); err != nil {
+		return err
+	}
+
+	fmt.Printf("Unlocked %s\n", path)
+	return nil
+}
+
+// Returns the secret of the credentials file:
+// the content of the key file if given, otherwise the passphrase of [GGS_PASSPHRASE] or the one entered.
+// The entered passphrase is asked twice if confirm is true.
+func readSecret(cc *cli.Context, confirm bool) ([]byte, error) {
+
+	if path := cc.String("key-file"); path != "" {
+		return util.ReadKeyFile(path)
+	}
+	if passphrase := os.Getenv("GGS_PASSPHRASE"); passphrase != "" {
+		return []byte(passphrase), nil
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+	passphrase, err := readPassphrase(reader, "Passphrase: ")
+	if err != nil {
+		return nil, err
+	}
+	if passphrase == "" {
+		return nil, errors.New("passphrase is empty")
+	}
+
+	if confirm {
+		again, err := readPassphrase(reader, "Passphrase (again): ")
+		if err != nil {
+			return nil, err
+		}
+		if again != passphrase {
+			return nil, errors.New("passphrases do not match")
+		}
+	}
+
+	return []byte(passphrase), nil
+}
+
+// Read a line from the standard input without echo on terminals.
+// stty is used to turn off echo, where the input is shown without it (e.g. on Windows).
+func readPassphrase(reader *bufio.Reader, prompt string) (string, error) {
+
+	fmt.Fprint(os.Stderr, prompt)
+
+	stty := exec.Command("stty", "-echo")
+	stty.Stdin = os.Stdin
+	if stty.Run() == nil {
+		defer func() {
+			restore := exec.Command("stty", "echo")
+			restore.Stdin = os.Stdin
+			_ = restore.Run()
+			fmt.Fprintln(os.Stderr)
+		}()
+	}
+
+	line, err := reader.ReadString('\n')
+	if err != nil && !(errors.Is(err, io.EOF) && line != "") {
+		return "", fmt.Errorf("failed to read the passphrase: %w", err)
+	}
+
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// Print the information about the token of the host.
+func PrintTokenInfo(w io.Writer, host string, info api.TokenInfo) {
+
+	scopes := strings.Join(info.Scopes, ", ")
+	if scopes == "" {
+		scopes = "-"
+	}
+	expiration := info.Expiration
+	if expiration == "" {
+		expiration = "-"
+	}
+
+	fmt.Fprintf(w, "Host:        %s\n", host)
+	fmt.Fprintf(w, "Login:       %s\n", info.Login)
+	fmt.Fprintf(w, "Token type:  %s\n", info.Type)
+	fmt.Fprintf(w, "Scopes:      %s\n", scopes)
+	fmt.Fprintf(w, "Expiration:  %s\n", expiration)
+}
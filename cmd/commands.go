@@ -1,9 +1,14 @@
 package cmd
 
 import (
+	"fmt"
+	"io"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/kokoichi206/go-git-stats/api"
+	"github.com/kokoichi206/go-git-stats/telemetry"
 	"github.com/kokoichi206/go-git-stats/util"
 	"github.com/urfave/cli/v2"
 )
@@ -15,24 +20,181 @@ type Cmd struct {
 	wait   *sync.WaitGroup
 	mutex  *sync.Mutex
 	total  int
+	rows   []repositoryLines
+	// Repositories skipped because of errors.
+	failures []repositoryFailure
+	// Semaphore of concurrent API calls, nil means unlimited.
+	limit chan struct{}
+	// Spans and metrics of the commands, nil means disabled.
+	telemetry *telemetry.Provider
+	// Options of the API callers which the commands create (e.g. auth and doctor).
+	options []api.Option
 }
 
 func New(config util.Config, api api.ApiCaller) Cmd {
 
-	return Cmd{
-		config: config,
-		api:    api,
-		wait:   &sync.WaitGroup{},
-		mutex:  &sync.Mutex{},
-		total:  0,
+	c := Cmd{
+		wait:  &sync.WaitGroup{},
+		mutex: &sync.Mutex{},
+		total: 0,
 	}
+	c.Configure(config, api)
+	return c
+}
+
+// Set the configurations and the api caller selected at startup.
+func (c *Cmd) Configure(config util.Config, api api.ApiCaller) {
+	c.config = config
+	c.api = api
+	c.limit = nil
+	if config.Concurrency > 0 {
+		c.limit = make(chan struct{}, config.Concurrency)
+	}
+}
+
+// Set the options of the API callers which the commands create.
+func (c *Cmd) SetApiOptions(options ...api.Option) {
+	c.options = options
+}
+
+// Record spans and metrics of the commands with the provider.
+func (c *Cmd) SetTelemetry(provider *telemetry.Provider) {
+	c.telemetry = provider
 }
 
 // Get all commands.
+// Errors of the commands are described for users,
+// and are redacted since they may have tokens (e.g. in responses).
 func (c *Cmd) NewCommands() []*cli.Command {
-	return []*cli.Command{
+	return c.wrapActions("", []*cli.Command{
 		c.RepoCommand(),
 		c.StatsCommand(),
 		c.LinesCommand(),
+		c.ParticipationCommand(),
+		c.LanguagesCommand(),
+		c.LocalCommand(),
+		c.AuthCommand(),
+		c.ConfigCommand(),
+		c.DoctorCommand(),
+	})
+}
+
+// Record the commands and their subcommands with the telemetry, and describe and redact their errors.
+// The name of subcommands has the parent (e.g. auth login).
+func (c *Cmd) wrapActions(parent string, commands []*cli.Command) []*cli.Command {
+
+	for _, command := range commands {
+		name := strings.TrimSpace(parent + " " + command.Name)
+		if action := command.Action; action != nil {
+			command.Action = func(cc *cli.Context) error {
+				span := c.telemetry.StartRoot("ggs "+name, telemetry.String("ggs.command", name))
+				start := time.Now()
+
+				err := util.RedactError(describeError(action(cc)))
+
+				status := "ok"
+				if err != nil {
+					status = "error"
+					span.RecordError(err)
+				}
+				span.End()
+				attrs := []telemetry.Attribute{telemetry.String("ggs.command", name), telemetry.String("ggs.status", status)}
+				c.telemetry.Counter("ggs.command.runs", "Runs of the commands.", "{run}").Add(1, attrs...)
+				c.telemetry.Histogram("ggs.command.duration", "Duration of the commands.", "ms", telemetry.DefaultLatencyBounds).
+					Record(float64(time.Since(start).Microseconds())/1000, attrs...)
+
+				return err
+			}
+		}
+		c.wrapActions(name, command.Subcommands)
+	}
+
+	return commands
+}
+
+// List target repositories of aggregate commands.
+// The username (or the default user of the configurations) takes precedence over the github access token,
+// and no repositories are returned when neither is given.
+// Repositories are filtered by the configurations.
+func (c *Cmd) listRepositories(userName string) ([]api.Repository, error) {
+
+	var repositories []api.Repository
+	var err error
+
+	if userName == "" {
+		userName = c.config.User
+	}
+
+	// With Github access token
+	if c.authenticated() {
+		repositories, err = c.api.ListRepositoriesForAuthenticatedUser()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// With username
+	if userName != "" {
+		repositories, err = c.api.ListPublicRepositories(userName)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return c.filterRepositories(repositories), nil
+}
+
+// Returns the repositories which pass the filter of the configurations.
+func (c *Cmd) filterRepositories(repositories []api.Repository) []api.Repository {
+
+	var filtered []api.Repository
+	for _, r := range repositories {
+		if c.config.Filter.Match(r.FullName) {
+			filtered = append(filtered, r)
+		}
+	}
+
+	return filtered
+}
+
+// Wait until an API call is allowed by the concurrency limit.
+// release must be called after the call.
+func (c *Cmd) acquire() {
+	if c.limit != nil {
+		c.limit <- struct{}{}
+	}
+}
+
+// Allow another API call.
+func (c *Cmd) release() {
+	if c.limit != nil {
+		<-c.limit
+	}
+}
+
+// Returns whether repositories of the authenticated user can be listed.
+func (c *Cmd) authenticated() bool {
+	return c.config.Authenticated()
+}
+
+// Print the usage of the tokens if requests are shared by the token pool.
+func (c *Cmd) PrintTokenUsage(w io.Writer) {
+
+	reporter, ok := c.api.(api.TokenUsageReporter)
+	if !ok {
+		return
+	}
+	usage := reporter.TokenUsage()
+	if len(usage) == 0 {
+		return
+	}
+
+	fmt.Fprintln(w, "Token usage:")
+	for _, u := range usage {
+		remaining := "-"
+		if u.Remaining >= 0 {
+			remaining = fmt.Sprintf("%d (reset at %s)", u.Remaining, u.Reset.Format("15:04"))
+		}
+		fmt.Fprintf(w, "  %-16s requests: %-6d remaining: %s\n", u.Token, u.Requests, remaining)
 	}
 }
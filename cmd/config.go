@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/kokoichi206/go-git-stats/util"
+	"github.com/urfave/cli/v2"
+)
+
+// Return cli command about the config file.
+func (c *Cmd) ConfigCommand() *cli.Command {
+	return &cli.Command{
+		Name:        "config",
+		Description: "Get and set settings in the config file (in the selected profile if any)",
+		Subcommands: []*cli.Command{
+			{
+				Name:        "get",
+				ArgsUsage:   "<key>",
+				Description: "Show the setting of the key in the config file",
+				Action:      c.getConfig,
+			},
+			{
+				Name:        "set",
+				ArgsUsage:   "<key> <value>",
+				Description: "Set the setting of the key in the config file, an empty value removes it",
+				Flags: []cli.Flag{
+					&cli.BoolFlag{Name: "global", Usage: "set at the top level of the config file even if a profile is selected"},
+				},
+				Action: c.setConfig,
+			},
+			{
+				Name:        "list",
+				Description: "Show the settings in the config file",
+				Action:      c.listConfig,
+			},
+			{
+				Name:        "path",
+				Description: "Show the path to the config file",
+				Action:      c.configPath,
+			},
+		},
+	}
+}
+
+// Show the setting of the key.
+func (c *Cmd) getConfig(cc *cli.Context) error {
+
+	if cc.NArg() != 1 {
+		return errors.New("usage: ggs config get <key>")
+	}
+
+	file, err := c.loadConfigFile()
+	if err != nil {
+		return err
+	}
+
+	value, err := file.Get(c.config.Profile, cc.Args().First())
+	if err != nil {
+		return err
+	}
+
+	// Values may have secrets by mistake.
+	fmt.Println(util.Redact(value))
+	return nil
+}
+
+// Set the setting of the key.
+func (c *Cmd) setConfig(cc *cli.Context) error {
+
+	if cc.NArg() != 2 {
+		return errors.New("usage: ggs config set <key> <value>")
+	}
+
+	path, err := c.configFilePath()
+	if err != nil {
+		return err
+	}
+
+	profile := c.config.Profile
+	if cc.Bool("global") {
+		profile = ""
+	}
+
+	return util.SetFileSetting(path, profile, cc.Args().Get(0), cc.Args().Get(1))
+}
+
+// Show the settings which are set in the config file.
+func (c *Cmd) listConfig(cc *cli.Context) error {
+
+	file, err := c.loadConfigFile()
+	if err != nil {
+		return err
+	}
+
+	for _, key := range util.SettingKeys {
+		value, err := file.Get(c.config.Profile, key)
+		if err != nil {
+			return err
+		}
+		if value != "" {
+			fmt.Printf("%s=%s\n", key, util.Redact(value))
+		}
+	}
+	if len(file.Hosts) > 0 {
+		fmt.Printf("hosts=%d hosts (edit the config file directly)\n", len(file.Hosts))
+	}
+	return nil
+}
+
+// Show the path to the config file.
+func (c *Cmd) configPath(cc *cli.Context) error {
+
+	path, err := c.configFilePath()
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(path)
+	return nil
+}
+
+// Load the config file in use.
+func (c *Cmd) loadConfigFile() (util.FileConfig, error) {
+
+	path, err := c.configFilePath()
+	if err != nil {
+		return util.FileConfig{}, err
+	}
+
+	return util.LoadFileConfig(path)
+}
+
+// Returns the path to the config file in use.
+func (c *Cmd) configFilePath() (string, error) {
+
+	if c.config.ConfigPath == "" {
+		return "", errors.New("path to the config file is unknown without the home directory: use -config or [GGS_CONFIG]")
+	}
+	return c.config.ConfigPath, nil
+}
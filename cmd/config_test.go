@@ -0,0 +1,86 @@
+package cmd_test
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kokoichi206/go-git-stats/cmd"
+	"github.com/kokoichi206/go-git-stats/util"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v2"
+)
+
+func TestConfigCommand(t *testing.T) {
+
+	path := filepath.Join(t.TempDir(), "ggs", "config.yml")
+
+	// Run the command with the profile selected at startup.
+	run := func(profile string, commands ...string) (string, error) {
+		c := cmd.New(util.Config{ConfigPath: path, Profile: profile}, nil)
+		app := cli.NewApp()
+		app.Commands = c.NewCommands()
+
+		// Prepare for standard output testing
+		stdOut := os.Stdout
+		r, w, _ := os.Pipe()
+		os.Stdout = w
+
+		err := app.Run(append([]string{""}, commands...))
+
+		_ = w.Close()
+		result, _ := io.ReadAll(r)
+		os.Stdout = stdOut
+		return string(result), err
+	}
+
+	// Path
+	output, err := run("", "config", "path")
+	require.NoError(t, err)
+	require.Equal(t, path+"\n", output)
+
+	// Set at the top level and in the profile
+	_, err = run("", "config", "set", "host", "ghe.example.com")
+	require.NoError(t, err)
+	_, err = run("work", "config", "set", "output", "json")
+	require.NoError(t, err)
+	_, err = run("work", "config", "set", "-global", "user", "kokoichi206")
+	require.NoError(t, err)
+
+	data, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, `host: ghe.example.com
+profiles:
+  work:
+    output: json
+user: kokoichi206
+`, string(data))
+
+	// Get
+	output, err = run("work", "config", "get", "output")
+	require.NoError(t, err)
+	require.Equal(t, "json\n", output)
+
+	output, err = run("", "config", "get", "output")
+	require.NoError(t, err)
+	require.Equal(t, "\n", output)
+
+	// List
+	output, err = run("work", "config", "list")
+	require.NoError(t, err)
+	require.Equal(t, "host=ghe.example.com\nuser=kokoichi206\noutput=json\n", output)
+
+	// Errors
+	_, err = run("", "config", "set", "output")
+	require.Error(t, err)
+	require.Equal(t, "usage: ggs config set <key> <value>", err.Error())
+
+	_, err = run("", "config", "set", "output", "xml")
+	require.Error(t, err)
+	require.Equal(t, "output must be text or json, but got 'xml'", err.Error())
+
+	_, err = run("", "config", "get", "token")
+	require.Error(t, err)
+}
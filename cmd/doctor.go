@@ -0,0 +1,297 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/kokoichi206/go-git-stats/api"
+	"github.com/kokoichi206/go-git-stats/util"
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	// Clock skew which breaks tokens of GitHub Apps (and expiration checks).
+	maxClockSkew = time.Minute
+	// Tokens which expire within this period should be renewed.
+	expirationWarning = 7 * 24 * time.Hour
+)
+
+// Return cli command about diagnostics.
+func (c *Cmd) DoctorCommand() *cli.Command {
+	return &cli.Command{
+		Name:        "doctor",
+		Description: "Check the configurations, the token, the API and the cache, and show how to fix problems",
+		Action:      c.doctor,
+	}
+}
+
+// Results of the checks.
+type diagnosis struct {
+	w        io.Writer
+	failures int
+	warnings int
+}
+
+// Print the result of a check with the fix (if any).
+func (d *diagnosis) report(level, message, fix string) {
+
+	switch level {
+	case "fail":
+		d.failures += 1
+	case "warn":
+		d.warnings += 1
+	}
+
+	fmt.Fprintf(d.w, "[%s] %s\n", level, message)
+	if fix != "" {
+		fmt.Fprintf(d.w, "       fix: %s\n", fix)
+	}
+}
+
+func (d *diagnosis) ok(format string, a ...interface{}) {
+	d.report("ok", fmt.Sprintf(format, a...), "")
+}
+
+// Check everything and print the results.
+// It fails if some checks fail, but not with warnings.
+func (c *Cmd) doctor(cc *cli.Context) error {
+
+	// Errors of the API may have secrets.
+	d := &diagnosis{w: util.NewRedactWriter(os.Stdout)}
+
+	c.diagnoseConfig(d)
+	diagnoseNetwork(d, c.config)
+	if c.config.Backend == util.BackendGitHub {
+		configs := []util.Config{c.config}
+		if len(c.config.Hosts) > 0 {
+			configs = nil
+			for _, h := range c.config.Hosts {
+				hostConfig, err := c.config.ForHost(h)
+				if err != nil {
+					return err
+				}
+				configs = append(configs, hostConfig)
+			}
+		}
+		for _, config := range configs {
+			diagnoseHost(d, config, c.options...)
+		}
+	} else {
+		d.ok("backend: %s (the token and the API are checked for %s only)", c.config.Backend, util.BackendGitHub)
+	}
+	diagnoseCache(d, c.config.Cache)
+
+	fmt.Println()
+	if d.failures > 0 {
+		return fmt.Errorf("doctor found problems: %d, warnings: %d", d.failures, d.warnings)
+	}
+	fmt.Printf("No problems found (warnings: %d).\n", d.warnings)
+	return nil
+}
+
+// Check the config file and problems found while loading the configurations.
+func (c *Cmd) diagnoseConfig(d *diagnosis) {
+
+	path := c.config.ConfigPath
+	profile := c.config.Profile
+	if profile == "" {
+		profile = "none"
+	}
+
+	if path == "" {
+		d.report("warn", "config file: unknown without the home directory", "Use -config or GGS_CONFIG.")
+	} else if _, err := os.Stat(path); err != nil {
+		d.ok("config file: %s does not exist, and defaults are used", path)
+	} else {
+		d.ok("config file: %s (profile: %s)", path, profile)
+	}
+
+	for _, p := range c.config.Problems {
+		d.report("fail", p.Message, p.Fix)
+	}
+
+	// Tokens of the locked file are skipped without the secret.
+	if path, err := util.CredentialsPath(); err == nil {
+		locked, err := util.IsCredentialsLocked(path)
+		if secret, _ := util.CredentialsSecret(); err == nil && locked && secret == nil {
+			d.report("warn", fmt.Sprintf("credentials file: %s is locked, so the stored tokens are not used", path),
+				"Set the passphrase to GGS_PASSPHRASE, or the path to the key file to GGS_KEY_FILE.")
+		}
+	}
+}
+
+// Check the proxy and the verification of certificates.
+func diagnoseNetwork(d *diagnosis, config util.Config) {
+
+	if config.Proxy != "" {
+		noProxy := config.NoProxy
+		if noProxy == "" {
+			noProxy = "none"
+		}
+		// Passwords in the URL are redacted by the writer.
+		d.ok("proxy: %s (excluded: %s)", config.Proxy, noProxy)
+	}
+
+	if config.InsecureSkipVerify {
+		d.report("warn", "certificates of servers are not verified, so tokens and data can be stolen on the network",
+			"Use -ca-cert (or GGS_CA_CERT) with the CA bundle of the server or the proxy instead of -insecure-skip-verify.")
+	}
+}
+
+// Check the token, the API, the clock and the rate limit of the GitHub host.
+func diagnoseHost(d *diagnosis, config util.Config, options ...api.Option) {
+
+	host := util.HostName(config.Host)
+	a := api.New(config, options...)
+	authenticated := config.Token != "" || config.App.Configured()
+
+	if authenticated {
+		d.ok("%s: token from %s", host, config.TokenSource)
+	} else {
+		d.report("warn", fmt.Sprintf("%s: no token is found, so only public data is available", host),
+			"Run 'ggs auth login', or set GGS_TOKEN.")
+	}
+
+	rateLimit, err := a.(api.RateLimitChecker).RateLimit()
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		d.report("fail", fmt.Sprintf("%s: API %s is not reachable: %v", host, config.ApiBaseURL, urlErr.Err),
+			"Check the network, -host (or GGS_HOST), -proxy (or HTTPS_PROXY) and -ca-cert (or GGS_CA_CERT).")
+		return
+	}
+	if err != nil {
+		d.report("fail", fmt.Sprintf("%s: API %s returns an error: %v", host, config.ApiBaseURL, err),
+			"The token may be expired or revoked. Create a new token, or run 'ggs auth login'.")
+		return
+	}
+	d.ok("%s: API %s is reachable", host, config.ApiBaseURL)
+
+	if authenticated {
+		diagnoseToken(d, host, a.(api.TokenChecker))
+	}
+
+	if !rateLimit.ServerTime.IsZero() {
+		skew := time.Since(rateLimit.ServerTime).Round(time.Second)
+		if skew > maxClockSkew || skew < -maxClockSkew {
+			d.report("warn", fmt.Sprintf("%s: the clock differs from the server by %s", host, skew),
+				"Synchronize the clock (e.g. with NTP). Tokens of GitHub Apps are rejected with a skewed clock.")
+		} else {
+			d.ok("%s: the clock is in sync with the server", host)
+		}
+	}
+
+	switch {
+	case !rateLimit.Enabled:
+		d.ok("%s: the rate limit is disabled", host)
+	case rateLimit.Remaining*10 < rateLimit.Limit:
+		fix := fmt.Sprintf("Wait until %s, or lower -concurrency.", rateLimit.Reset.Format("15:04"))
+		if !authenticated {
+			fix = fmt.Sprintf("Wait until %s, or set a token for the higher rate limit.", rateLimit.Reset.Format("15:04"))
+		}
+		d.report("warn", fmt.Sprintf("%s: only %d of %d requests are left", host, rateLimit.Remaining, rateLimit.Limit), fix)
+	default:
+		d.ok("%s: %d of %d requests are left until %s", host, rateLimit.Remaining, rateLimit.Limit, rateLimit.Reset.Format("15:04"))
+	}
+}
+
+// Check that the token is valid and has the scopes and time to run the commands.
+func diagnoseToken(d *diagnosis, host string, checker api.TokenChecker) {
+
+	info, err := checker.CheckToken()
+	if err != nil {
+		d.report("fail", fmt.Sprintf("%s: the token is not valid: %v", host, err),
+			"The token may be expired or revoked. Create a new token, or run 'ggs auth login'.")
+		return
+	}
+	d.ok("%s: logged in as %s with %s", host, info.Login, info.Type)
+
+	// Only classic and OAuth tokens have scopes.
+	if info.Type == util.TokenTypeClassic || info.Type == util.TokenTypeOAuth {
+		if !contains(info.Scopes, "repo") {
+			d.report("warn", fmt.Sprintf("%s: the token does not have the repo scope (scopes: %s), so private repositories are not counted", host, strings.Join(info.Scopes, ", ")),
+				"Add the repo scope to the token, or run 'ggs auth login'.")
+		} else {
+			d.ok("%s: the token has the repo scope", host)
+		}
+	}
+
+	if info.Expiration != "" {
+		expiration, err := parseExpiration(info.Expiration)
+		if err == nil && time.Until(expiration) < expirationWarning {
+			d.report("warn", fmt.Sprintf("%s: the token expires at %s", host, info.Expiration),
+				"Renew the token before it expires.")
+		}
+	}
+}
+
+// Check that the cache directory is writable and private.
+func diagnoseCache(d *diagnosis, cache util.CacheConfig) {
+
+	if !cache.Enabled() {
+		d.ok("cache: disabled")
+		return
+	}
+
+	dir := cache.Dir
+	if dir == "" {
+		var err error
+		if dir, err = util.CacheDir(); err != nil {
+			d.report("warn", "cache: the directory is unknown, so responses are not cached", "Set the directory with 'ggs config set cache.dir <dir>'.")
+			return
+		}
+	}
+
+	info, err := os.Stat(dir)
+	if os.IsNotExist(err) {
+		d.ok("cache: %s will be created (ttl %s)", dir, cache.TTL)
+		return
+	}
+	if err != nil || !info.IsDir() {
+		d.report("fail", fmt.Sprintf("cache: %s is not a directory", dir),
+			"Remove it, or set another directory with 'ggs config set cache.dir <dir>'.")
+		return
+	}
+
+	f, err := ioutil.TempFile(dir, ".doctor-")
+	if err != nil {
+		d.report("fail", fmt.Sprintf("cache: %s is not writable", dir),
+			fmt.Sprintf("Fix the owner and the permission of %s, or set another directory with 'ggs config set cache.dir <dir>'.", dir))
+		return
+	}
+	f.Close()
+	os.Remove(f.Name())
+
+	if info.Mode().Perm()&0077 != 0 {
+		d.report("warn", fmt.Sprintf("cache: %s can be read by other users (%s)", dir, info.Mode().Perm()),
+			fmt.Sprintf("Run 'chmod 700 %s', since cached responses may have private data.", dir))
+		return
+	}
+	d.ok("cache: %s (ttl %s)", dir, cache.TTL)
+}
+
+// Parse the expiration of the token in the formats of GitHub and installation tokens.
+func parseExpiration(expiration string) (time.Time, error) {
+
+	for _, layout := range []string{"2006-01-02 15:04:05 MST", "2006-01-02 15:04:05 -0700"} {
+		if t, err := time.Parse(layout, expiration); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unknown format of the expiration: %s", expiration)
+}
+
+// Returns whether the values contain the value.
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
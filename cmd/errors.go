@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+
+	"github.com/kokoichi206/go-git-stats/api"
+	"github.com/kokoichi206/go-git-stats/util"
+)
+
+// Error with the message for users, which still matches the original error with errors.Is and errors.As.
+type describedError struct {
+	message string
+	err     error
+}
+
+func (e *describedError) Error() string {
+	return e.message
+}
+
+func (e *describedError) Unwrap() error {
+	return e.err
+}
+
+// Returns the error whose message tells users what happened and what to do for errors of responses.
+// Other errors are returned as they are.
+func describeError(err error) error {
+
+	var re *api.RateLimitError
+	if errors.As(err, &re) {
+		message := fmt.Sprintf("the rate limit of %s is exceeded", hostOf(re.URL))
+		if !re.Reset.IsZero() {
+			message += fmt.Sprintf(" until %s", re.Reset.Local().Format("15:04:05"))
+		}
+		message += ". Wait for the reset, or share requests across tokens with GGS_TOKENS."
+		return &describedError{message: message, err: err}
+	}
+
+	var se *api.StatusError
+	if !errors.As(err, &se) {
+		return err
+	}
+
+	reason := ""
+	if se.Message != "" {
+		reason = fmt.Sprintf(" (%s)", se.Message)
+	}
+
+	var message string
+	switch {
+	case errors.Is(se, api.ErrUnauthorized):
+		message = fmt.Sprintf("%s rejected the token%s. Check the token with 'ggs doctor', or log in again with 'ggs auth login'.", hostOf(se.URL), reason)
+	case errors.Is(se, api.ErrForbidden):
+		message = fmt.Sprintf("the token is not allowed to access %s%s. Check the scopes of the token with 'ggs auth status'.", resourceOf(se.URL), reason)
+	case errors.Is(se, api.ErrNotFound):
+		message = fmt.Sprintf("%s is not found%s. Check the name, and that the token can access it if it is private.", resourceOf(se.URL), reason)
+	case errors.Is(se, api.ErrStatsPending):
+		message = fmt.Sprintf("statistics of %s are being computed by GitHub. Try again in a minute.", resourceOf(se.URL))
+	default:
+		return err
+	}
+
+	return &describedError{message: message, err: err}
+}
+
+// Returns the host of the URL, or the URL itself if it is not parsed.
+func hostOf(URL string) string {
+
+	u, err := url.Parse(URL)
+	if err != nil || u.Host == "" {
+		return URL
+	}
+	return u.Host
+}
+
+// Returns the repository (owner/name) of the URL of GitHub,
+// or the URL without the query for others.
+func resourceOf(URL string) string {
+
+	u, err := url.Parse(URL)
+	if err != nil {
+		return URL
+	}
+
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+	for i, segment := range segments {
+		if segment == "repos" && i+2 < len(segments) {
+			return segments[i+1] + "/" + segments[i+2]
+		}
+	}
+
+	u.RawQuery = ""
+	return u.String()
+}
+
+// Error of a repository which is skipped by aggregate commands.
+type repositoryFailure struct {
+	fullName string
+	err      error
+}
+
+// Record the error of the repository, which is called from goroutines.
+func (c *Cmd) fail(fullName string, err error) {
+	c.mutex.Lock()
+	c.failures = append(c.failures, repositoryFailure{fullName: fullName, err: err})
+	c.mutex.Unlock()
+}
+
+// Returns the error which stops the command, since other repositories fail as well:
+// the rate limit is exceeded, the token is rejected or the backend does not support the call.
+// Otherwise the skipped repositories are reported to w, since the results are partial.
+func (c *Cmd) checkFailures(w io.Writer) error {
+
+	for _, f := range c.failures {
+		if errors.Is(f.err, api.ErrRateLimited) || errors.Is(f.err, api.ErrUnauthorized) || errors.Is(f.err, api.ErrNotSupported) {
+			return fmt.Errorf("%s: %w", f.fullName, f.err)
+		}
+	}
+
+	if len(c.failures) == 0 {
+		return nil
+	}
+	fmt.Fprintf(w, "Warning: %d repositories are skipped because of errors:\n", len(c.failures))
+	for _, f := range c.failures {
+		// Errors may have tokens (e.g. in responses).
+		fmt.Fprintf(w, "  %s: %s\n", f.fullName, util.Redact(describeError(f.err).Error()))
+	}
+	return nil
+}
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"sync"
+	"time"
 
 	"github.com/kokoichi206/go-git-stats/api/mock"
 	"github.com/kokoichi206/go-git-stats/util"
@@ -18,7 +19,14 @@ func ExportNewCommandWithMock(config util.Config, mockApi *mock.MockApi) Cmd {
 
 func (c *Cmd) ExportInit() {
 	c.total = 0
+	c.rows = nil
+	c.failures = nil
 	c.config.Token = ""
+	c.config.Backend = util.BackendGitHub
+}
+
+func (c *Cmd) ExportSetBackend(backend string) {
+	c.config.Backend = backend
 }
 
 func (c *Cmd) ExportSetToken(token string) {
@@ -28,3 +36,12 @@ func (c *Cmd) ExportSetToken(token string) {
 func (c *Cmd) ExportGetTotal() int {
 	return c.total
 }
+
+// Fix the current time, and returns the function to restore it.
+func ExportSetNow(t time.Time) func() {
+	old := now
+	now = func() time.Time { return t }
+	return func() {
+		now = old
+	}
+}
@@ -1,12 +1,20 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"os"
+	"time"
 
 	"github.com/kokoichi206/go-git-stats/api"
+	"github.com/kokoichi206/go-git-stats/api/bitbucket"
+	"github.com/kokoichi206/go-git-stats/api/gitea"
+	"github.com/kokoichi206/go-git-stats/api/gitlab"
+	"github.com/kokoichi206/go-git-stats/api/local"
+	"github.com/kokoichi206/go-git-stats/api/multi"
 	"github.com/kokoichi206/go-git-stats/cmd"
+	"github.com/kokoichi206/go-git-stats/telemetry"
 	"github.com/kokoichi206/go-git-stats/util"
 	"github.com/urfave/cli/v2"
 )
@@ -20,28 +28,397 @@ var (
 
 func main() {
 
-	config, err := util.LoadConfig()
-	if err != nil {
-		fmt.Println(err.Error())
-		// When the configuration error occures, the following steps should not be executed.
-		os.Exit(1)
-	}
-
-	api := api.New(config)
-	cmd := cmd.New(config, api)
+	// Errors may have tokens (e.g. in responses of APIs).
+	log.SetOutput(util.NewRedactWriter(os.Stderr))
 
-	app := newApp(cmd)
+	app := newApp()
 
 	if err := app.Run(os.Args); err != nil {
 		log.Fatal(err)
 	}
 }
 
-func newApp(c cmd.Cmd) *cli.App {
+func newApp() *cli.App {
+
+	c := cmd.New(util.Config{}, nil)
+	// Provider of the telemetry, nil if disabled.
+	var provider *telemetry.Provider
+
+	// -v is for -debug.
+	cli.VersionFlag = &cli.BoolFlag{Name: "version", Usage: "print the version"}
+
 	return &cli.App{
-		Name:     "ggs",
-		Usage:    "Go git stats cli",
-		Version:  fmt.Sprintf("%s (rev:%s)", version, revision),
+		Name:    "ggs",
+		Usage:   "Go git stats cli",
+		Version: fmt.Sprintf("%s (rev:%s)", version, revision),
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "config",
+				Usage: "path to the config file (default: $GGS_CONFIG or config.yml in $XDG_CONFIG_HOME/ggs)",
+			},
+			&cli.StringFlag{
+				Name:  "profile",
+				Usage: "profile in the config file (default: $GGS_PROFILE or profile in the config file)",
+			},
+			&cli.StringFlag{
+				Name:    "backend",
+				Aliases: []string{"provider"},
+				Value:   util.BackendGitHub,
+				Usage:   fmt.Sprintf("backend to get statistics from (%s, %s, %s, %s, %s, %s)", util.BackendGitHub, util.BackendGitLab, util.BackendGitea, util.BackendBitbucket, util.BackendBitbucketServer, util.BackendLocal),
+				EnvVars: []string{"GGS_BACKEND"},
+			},
+			&cli.StringFlag{
+				Name:  "host",
+				Usage: "GitHub host, e.g. ghe.example.com for GitHub Enterprise Server (default: $GGS_HOST, host in config.yml or github.com)",
+			},
+			&cli.StringFlag{
+				Name:  "ca-cert",
+				Usage: "path to a PEM encoded CA bundle to verify servers (default: $GGS_CA_CERT or ca_cert in config.yml)",
+			},
+			&cli.StringFlag{
+				Name:  "proxy",
+				Usage: "URL of the proxy of all requests, hosts in $NO_PROXY are excluded (default: $GGS_PROXY, proxy in config.yml, $HTTPS_PROXY or $HTTP_PROXY)",
+			},
+			&cli.BoolFlag{
+				Name:  "insecure-skip-verify",
+				Usage: "do not verify certificates of servers, which is insecure (default: $GGS_INSECURE_SKIP_VERIFY)",
+			},
+			&cli.BoolFlag{
+				Name:    "verbose",
+				Usage:   "show where the GitHub access token comes from",
+				EnvVars: []string{"GGS_VERBOSE"},
+			},
+			&cli.BoolFlag{
+				Name:    "debug",
+				Aliases: []string{"v"},
+				Usage:   "log every request to the GitHub API to the standard error (tokens are redacted)",
+				EnvVars: []string{"GGS_DEBUG"},
+			},
+			&cli.StringFlag{
+				Name:    "debug-format",
+				Value:   util.OutputText,
+				Usage:   fmt.Sprintf("format of the logs of -debug (%s or %s)", util.OutputText, util.OutputJSON),
+				EnvVars: []string{"GGS_DEBUG_FORMAT"},
+			},
+			&cli.StringFlag{
+				Name:  "telemetry",
+				Usage: fmt.Sprintf("export spans and metrics of commands and API calls (%s, %s or %s, default: $GGS_TELEMETRY or telemetry.exporter in the config file)", util.TelemetryStdout, util.TelemetryStderr, util.TelemetryOTLP),
+			},
+			&cli.StringFlag{
+				Name:  "telemetry-endpoint",
+				Usage: fmt.Sprintf("base URL of OTLP/HTTP of -telemetry otlp (default: $GGS_TELEMETRY_ENDPOINT, telemetry.endpoint in the config file, $OTEL_EXPORTER_OTLP_ENDPOINT or %s)", util.DefaultOTLPEndpoint),
+			},
+			&cli.BoolFlag{
+				Name:    "check-token",
+				Usage:   "check the GitHub access token online and show its login, type, scopes and expiration before the command",
+				EnvVars: []string{"GGS_CHECK_TOKEN"},
+			},
+			&cli.StringFlag{
+				Name:    "path",
+				Value:   ".",
+				Usage:   "path to a git repository or a directory of them (local backend)",
+				EnvVars: []string{"GGS_PATH"},
+			},
+			&cli.StringFlag{
+				Name:  "gitlab-url",
+				Usage: "base URL of GitLab (gitlab backend, default: $GGS_GITLAB_URL or https://gitlab.com)",
+			},
+			&cli.StringFlag{
+				Name:  "gitea-url",
+				Usage: "base URL of Gitea or Forgejo (gitea backend, default: $GGS_GITEA_URL or https://gitea.com)",
+			},
+			&cli.StringFlag{
+				Name:  "bitbucket-url",
+				Usage: "base URL of Bitbucket Cloud API or Bitbucket Server (bitbucket and bitbucket-server backends, default: $GGS_BITBUCKET_URL or https://api.bitbucket.org, $GGS_BITBUCKET_SERVER_URL)",
+			},
+			&cli.StringFlag{
+				Name:  "output",
+				Usage: fmt.Sprintf("output format (%s, %s, default: $GGS_OUTPUT, output in the config file or %s)", util.OutputText, util.OutputJSON, util.OutputText),
+			},
+			&cli.IntFlag{
+				Name:  "concurrency",
+				Usage: "maximum number of concurrent API calls, 0 means unlimited (default: $GGS_CONCURRENCY, concurrency in the config file or 0)",
+			},
+			&cli.DurationFlag{
+				Name:  "cache-ttl",
+				Usage: "how long API responses are cached, e.g. 1h, 0 disables the cache (default: $GGS_CACHE_TTL, cache.ttl in the config file or 0)",
+			},
+		},
+		// Select the backend after global flags are parsed.
+		Before: func(cc *cli.Context) error {
+			config, err := util.LoadConfigWith(util.ConfigOptions{
+				Path:    cc.String("config"),
+				Profile: cc.String("profile"),
+			})
+			if err != nil {
+				return err
+			}
+
+			config.Backend = cc.String("backend")
+			config.LocalPath = cc.String("path")
+			if cc.IsSet("host") {
+				if err := config.SetHost(cc.String("host")); err != nil {
+					return err
+				}
+				if err := config.ResolveToken(); err != nil {
+					return err
+				}
+				// The single host is selected.
+				config.Hosts = nil
+			}
+			if cc.IsSet("ca-cert") {
+				if err := config.SetCACert(cc.String("ca-cert")); err != nil {
+					return err
+				}
+			}
+			if cc.IsSet("proxy") {
+				config.Proxy = cc.String("proxy")
+			}
+			if cc.IsSet("insecure-skip-verify") {
+				config.InsecureSkipVerify = cc.Bool("insecure-skip-verify")
+			}
+			if cc.IsSet("gitlab-url") {
+				config.GitLab.BaseURL = cc.String("gitlab-url")
+			}
+			if cc.IsSet("gitea-url") {
+				config.Gitea.BaseURL = cc.String("gitea-url")
+			}
+			if cc.IsSet("bitbucket-url") {
+				config.Bitbucket.BaseURL = cc.String("bitbucket-url")
+				config.BitbucketServer.BaseURL = cc.String("bitbucket-url")
+			}
+			if cc.IsSet("output") {
+				config.Output = cc.String("output")
+			}
+			if cc.IsSet("concurrency") {
+				config.Concurrency = cc.Int("concurrency")
+			}
+			if cc.IsSet("cache-ttl") {
+				config.Cache.TTL = cc.Duration("cache-ttl")
+			}
+			if cc.IsSet("telemetry") {
+				config.Telemetry.Exporter = cc.String("telemetry")
+			}
+			if cc.IsSet("telemetry-endpoint") {
+				config.Telemetry.Endpoint = cc.String("telemetry-endpoint")
+			}
+			// Only the values of the flags, since settings of the config file are left as problems.
+			flags := util.Config{
+				Output:      cc.String("output"),
+				Concurrency: cc.Int("concurrency"),
+				Cache:       util.CacheConfig{TTL: cc.Duration("cache-ttl")},
+				Proxy:       cc.String("proxy"),
+				Telemetry:   util.TelemetryConfig{Exporter: cc.String("telemetry"), Endpoint: cc.String("telemetry-endpoint")},
+			}
+			if err := flags.Validate(); err != nil {
+				return err
+			}
+			// The commands which fix or diagnose problems run with them.
+			if len(config.Problems) > 0 && !diagnostic[cc.Args().First()] {
+				return fmt.Errorf("%s\n%s\nRun 'ggs doctor' for details.", config.Problems[0].Message, config.Problems[0].Fix)
+			}
+
+			if config.InsecureSkipVerify {
+				fmt.Fprintln(os.Stderr, "Warning: certificates of servers are not verified, so tokens and data can be stolen on the network. Use -ca-cert instead if possible.")
+			}
+
+			logger, err := newLogger(cc)
+			if err != nil {
+				return err
+			}
+
+			if (cc.Bool("verbose") || logger != nil) && config.Backend == util.BackendGitHub {
+				printTokenSources(config)
+			}
+
+			// The options apply to every backend, and to the API calls of the commands (e.g. auth and doctor).
+			options := []api.Option{api.WithUserAgent("ggs/" + version)}
+			if logger != nil {
+				options = append(options, api.WithLogger(logger))
+			}
+			if provider = newTelemetry(config); provider != nil {
+				c.SetTelemetry(provider)
+				options = append(options, api.WithTelemetry(provider))
+			}
+			c.SetApiOptions(options...)
+
+			if cc.Bool("check-token") && config.Backend == util.BackendGitHub {
+				if err := checkTokens(config, options...); err != nil {
+					return err
+				}
+			}
+
+			a, err := newApiCaller(config, options...)
+			if err != nil {
+				return err
+			}
+			c.Configure(config, a)
+			return nil
+		},
+		// Report the usage of the tokens of the token pool (if any), and export the telemetry.
+		After: func(cc *cli.Context) error {
+			c.PrintTokenUsage(os.Stderr)
+
+			ctx, cancel := context.WithTimeout(context.Background(), telemetryTimeout)
+			defer cancel()
+			// Failures of the telemetry do not fail the command.
+			if err := provider.Shutdown(ctx); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: %s\n", util.Redact(err.Error()))
+			}
+			return nil
+		},
 		Commands: c.NewCommands(),
 	}
 }
+
+// Commands which run with problems of the configurations.
+// auth is among them to fix the credentials file (e.g. 'ggs auth unlock').
+var diagnostic = map[string]bool{
+	"auth":   true,
+	"config": true,
+	"doctor": true,
+	"help":   true,
+	"h":      true,
+}
+
+// Returns the logger of API requests of -debug, nil without it.
+func newLogger(cc *cli.Context) (api.Logger, error) {
+
+	if !cc.Bool("debug") {
+		return nil, nil
+	}
+
+	switch format := cc.String("debug-format"); format {
+	case util.OutputText:
+		return api.NewTextLogger(os.Stderr), nil
+	case util.OutputJSON:
+		return api.NewJSONLogger(os.Stderr), nil
+	default:
+		return nil, fmt.Errorf("debug format must be %s or %s, but got '%s'", util.OutputText, util.OutputJSON, format)
+	}
+}
+
+// Time limit of exporting the telemetry at exit.
+const telemetryTimeout = 10 * time.Second
+
+// Returns the provider of the telemetry of the configurations, nil if disabled.
+func newTelemetry(config util.Config) *telemetry.Provider {
+
+	var exporter telemetry.Exporter
+	switch config.Telemetry.Exporter {
+	case util.TelemetryStdout:
+		exporter = telemetry.NewWriterExporter(os.Stdout)
+	case util.TelemetryStderr:
+		exporter = telemetry.NewWriterExporter(os.Stderr)
+	case util.TelemetryOTLP:
+		// The collector is reached with the proxy and the CA bundle (POST requests are not cached).
+		exporter = telemetry.NewOTLPExporter(config.Telemetry.OTLPEndpoint(), config.HTTPClient())
+	default:
+		return nil
+	}
+
+	return telemetry.NewProvider(exporter,
+		telemetry.String("service.name", "ggs"),
+		telemetry.String("service.version", version),
+	)
+}
+
+// Create the api caller of the configured backend.
+// The options apply to the backends which send requests (all but local).
+func newApiCaller(config util.Config, options ...api.Option) (api.ApiCaller, error) {
+	switch config.Backend {
+	case util.BackendGitHub:
+		if len(config.Hosts) > 0 {
+			return newMultiHost(config, options...)
+		}
+		return api.New(config, options...), nil
+	case util.BackendLocal:
+		return local.New(config), nil
+	case util.BackendGitLab:
+		return gitlab.New(config, options...), nil
+	case util.BackendGitea:
+		return gitea.New(config, options...), nil
+	case util.BackendBitbucket:
+		return bitbucket.New(config, options...), nil
+	case util.BackendBitbucketServer:
+		if config.BitbucketServer.BaseURL == "" {
+			return nil, fmt.Errorf("base URL of Bitbucket Server is not set: use -bitbucket-url or [GGS_BITBUCKET_SERVER_URL]")
+		}
+		return bitbucket.NewServer(config, options...), nil
+	default:
+		return nil, fmt.Errorf("unknown backend: '%s'", config.Backend)
+	}
+}
+
+// Create the api caller which merges results of the configured GitHub hosts.
+func newMultiHost(config util.Config, options ...api.Option) (api.ApiCaller, error) {
+
+	hosts := make([]multi.Host, 0, len(config.Hosts))
+	for _, h := range config.Hosts {
+		hostConfig, err := config.ForHost(h)
+		if err != nil {
+			return nil, err
+		}
+		hosts = append(hosts, multi.Host{
+			Name:          util.HostName(h.Host),
+			Api:           api.New(hostConfig, options...),
+			Authenticated: hostConfig.Token != "",
+		})
+	}
+
+	return multi.New(hosts), nil
+}
+
+// Check the GitHub access token of each host and show the information.
+// It is written to the standard error not to mix with the output of the command.
+func checkTokens(config util.Config, options ...api.Option) error {
+
+	configs := []util.Config{config}
+	if len(config.Hosts) > 0 {
+		configs = nil
+		for _, h := range config.Hosts {
+			hostConfig, err := config.ForHost(h)
+			if err != nil {
+				return err
+			}
+			configs = append(configs, hostConfig)
+		}
+	}
+
+	for _, hostConfig := range configs {
+		host := util.HostName(hostConfig.Host)
+		if hostConfig.Token == "" && !hostConfig.App.Configured() {
+			fmt.Fprintf(os.Stderr, "Host:        %s\n", host)
+			fmt.Fprintln(os.Stderr, "Token:       not set")
+			continue
+		}
+
+		info, err := api.New(hostConfig, options...).(api.TokenChecker).CheckToken()
+		if err != nil {
+			return fmt.Errorf("failed to check the token of %s: %w", host, err)
+		}
+		cmd.PrintTokenInfo(os.Stderr, host, info)
+	}
+
+	return nil
+}
+
+// Show where the GitHub access token of each host comes from.
+func printTokenSources(config util.Config) {
+
+	hosts := config.Hosts
+	if len(hosts) == 0 {
+		hosts = []util.HostConfig{{Host: config.Host, Token: config.Token, TokenSource: config.TokenSource}}
+	}
+
+	for _, h := range hosts {
+		source := h.TokenSource
+		if source == "" {
+			source = "not found (public data only)"
+		}
+		fmt.Fprintf(os.Stderr, "Token of %s: %s\n", util.HostName(h.Host), source)
+	}
+	if pool := config.TokenPool(); len(config.Hosts) == 0 && len(pool) > 1 {
+		fmt.Fprintf(os.Stderr, "Requests are shared by %d tokens\n", len(pool))
+	}
+}
@@ -0,0 +1,159 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+)
+
+// Width of the bar of a language with 100%.
+const barWidth = 40
+
+// Return cli command about languages.
+func (c *Cmd) LanguagesCommand() *cli.Command {
+	return &cli.Command{
+		Name:        "languages",
+		Aliases:     []string{"lang"},
+		Description: "Get language breakdown across repositories",
+		Flags: []cli.Flag{
+			&cli.StringFlag{Name: "name", Aliases: []string{"n"}},
+			&cli.BoolFlag{Name: "detail", Aliases: []string{"d"}, Usage: "show languages of each repository"},
+		},
+		Action: c.getLanguages,
+	}
+}
+
+// Language and its count (bytes, lines, etc.).
+type languageCount struct {
+	name  string
+	count int
+}
+
+// Get languages of all repositories and aggregate their byte counts.
+func (c *Cmd) getLanguages(cc *cli.Context) error {
+
+	repositories, err := c.listRepositories(cc.String("name"))
+	if err != nil {
+		return err
+	}
+
+	perRepo := make(map[string]map[string]int, len(repositories))
+
+	c.wait.Add(len(repositories))
+	for _, repository := range repositories {
+		go func(fullName string) {
+			// Always decrements the WaitGroup counter.
+			defer c.wait.Done()
+			c.acquire()
+			defer c.release()
+
+			languages, err := c.api.Languages(fullName)
+			if err != nil {
+				c.fail(fullName, err)
+				return
+			}
+
+			c.mutex.Lock()
+			perRepo[fullName] = languages
+			c.mutex.Unlock()
+		}(repository.FullName)
+	}
+	c.wait.Wait()
+	if err := c.checkFailures(os.Stderr); err != nil {
+		return err
+	}
+
+	total := make(map[string]int)
+	for _, languages := range perRepo {
+		for name, bytes := range languages {
+			total[name] += bytes
+		}
+	}
+
+	if c.jsonOutput() {
+		report := struct {
+			Languages    []languageJSON            `json:"languages"`
+			Repositories map[string][]languageJSON `json:"repositories,omitempty"`
+		}{Languages: languagesJSON(total)}
+		if cc.Bool("detail") {
+			report.Repositories = make(map[string][]languageJSON, len(perRepo))
+			for fullName, languages := range perRepo {
+				report.Repositories[fullName] = languagesJSON(languages)
+			}
+		}
+		return printJSON(report)
+	}
+
+	printLanguages(total)
+
+	if cc.Bool("detail") {
+		for _, repository := range repositories {
+			languages, ok := perRepo[repository.FullName]
+			if !ok {
+				continue
+			}
+			fmt.Println()
+			fmt.Println(repository.FullName)
+			printLanguages(languages)
+		}
+	}
+
+	return nil
+}
+
+// Bytes of a language in JSON.
+type languageJSON struct {
+	Name  string `json:"name"`
+	Bytes int    `json:"bytes"`
+}
+
+// Languages in descending order of bytes in JSON.
+func languagesJSON(languages map[string]int) []languageJSON {
+
+	sorted := make([]languageCount, 0, len(languages))
+	for name, bytes := range languages {
+		sorted = append(sorted, languageCount{name: name, count: bytes})
+	}
+	sortByCount(sorted)
+
+	result := make([]languageJSON, 0, len(sorted))
+	for _, l := range sorted {
+		result = append(result, languageJSON{Name: l.name, Bytes: l.count})
+	}
+	return result
+}
+
+// Print languages in descending order of bytes with percentages and bars.
+func printLanguages(languages map[string]int) {
+
+	sum := 0
+	sorted := make([]languageCount, 0, len(languages))
+	for name, bytes := range languages {
+		sum += bytes
+		sorted = append(sorted, languageCount{name: name, count: bytes})
+	}
+	sortByCount(sorted)
+
+	fmt.Printf("%-20s\t%12s\t%7s\n", "Language", "Bytes", "Percent")
+	for _, l := range sorted {
+		// No percentages and bars when every byte count is zero.
+		bar := ""
+		if sum > 0 {
+			bar = strings.Repeat("█", int(float64(l.count)*barWidth/float64(sum)+0.5))
+		}
+		fmt.Printf("%-20s\t%12d\t%7s\t%s\n", l.name, l.count, ratio(l.count, sum), bar)
+	}
+}
+
+// Sort in descending order of counts (and ascending order of names for ties).
+func sortByCount(counts []languageCount) {
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].count == counts[j].count {
+			return counts[i].name < counts[j].name
+		}
+		return counts[i].count > counts[j].count
+	})
+}
@@ -0,0 +1,173 @@
+package cmd_test
+
+import (
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/kokoichi206/go-git-stats/api"
+	"github.com/kokoichi206/go-git-stats/api/mock"
+	"github.com/kokoichi206/go-git-stats/cmd"
+	"github.com/kokoichi206/go-git-stats/util"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v2"
+)
+
+func TestLanguagesCommand(t *testing.T) {
+
+	config, _ := util.LoadConfig()
+	mockApi := mock.New(config)
+
+	c := cmd.ExportNewCommandWithMock(config, mockApi)
+
+	app := cli.NewApp()
+	app.Commands = c.NewCommands()
+
+	repositories := []api.Repository{
+		{
+			ID:       489517307,
+			Private:  false,
+			Name:     "account-book-api",
+			FullName: "kokoichi206/account-book-api",
+		},
+		{
+			ID:       429817377,
+			Private:  false,
+			Name:     "utils",
+			FullName: "kokoichi206/utils",
+		},
+	}
+
+	testCases := []struct {
+		name      string
+		commands  []string
+		setup     func()
+		assertion func(t *testing.T, err error, api *mock.MockApi, output string)
+		tearDown  func()
+	}{
+		{
+			name:     "OK",
+			commands: []string{"", "languages", "-n", "kokoichi206"},
+			setup: func() {
+				mockApi.ListRepos = repositories
+				mockApi.ListLanguages = map[string]map[string]int{
+					"kokoichi206/account-book-api": {"Go": 600, "Shell": 100},
+					"kokoichi206/utils":            {"Go": 150, "Python": 150},
+				}
+			},
+			assertion: func(t *testing.T, err error, api *mock.MockApi, output string) {
+				require.NoError(t, err)
+				require.True(t, api.PublicCalled)
+				require.True(t, api.LanguagesCalled)
+
+				t.Log(output)
+				lines := strings.Split(output, "\n")
+				// Header and 3 languages in descending order of bytes.
+				require.True(t, strings.HasPrefix(lines[1], "Go"))
+				require.True(t, strings.Contains(lines[1], "750"))
+				require.True(t, strings.Contains(lines[1], "75.0%"))
+				require.True(t, strings.Contains(lines[1], strings.Repeat("█", 30)))
+				require.True(t, strings.HasPrefix(lines[2], "Python"))
+				require.True(t, strings.HasPrefix(lines[3], "Shell"))
+				require.True(t, strings.Contains(lines[3], "10.0%"))
+
+				// No details without the flag
+				require.False(t, strings.Contains(output, "kokoichi206/utils"))
+			},
+			tearDown: func() {
+				mockApi.InitMock()
+			},
+		},
+		{
+			name:     "OK with detail",
+			commands: []string{"", "lang", "-n", "kokoichi206", "-d"},
+			setup: func() {
+				mockApi.ListRepos = repositories
+				mockApi.ListLanguages = map[string]map[string]int{
+					"kokoichi206/account-book-api": {"Go": 600, "Shell": 100},
+					"kokoichi206/utils":            {"Go": 150, "Python": 150},
+				}
+			},
+			assertion: func(t *testing.T, err error, api *mock.MockApi, output string) {
+				require.NoError(t, err)
+
+				t.Log(output)
+				require.True(t, strings.Contains(output, "kokoichi206/account-book-api"))
+				require.True(t, strings.Contains(output, "kokoichi206/utils"))
+				// Python is half of the utils repository
+				require.True(t, strings.Contains(output, "50.0%"))
+			},
+			tearDown: func() {
+				mockApi.InitMock()
+			},
+		},
+		{
+			name:     "OK with zero bytes",
+			commands: []string{"", "languages", "-n", "kokoichi206"},
+			setup: func() {
+				mockApi.ListRepos = repositories
+				mockApi.ListLanguages = map[string]map[string]int{
+					"kokoichi206/account-book-api": {"Go": 0},
+				}
+			},
+			assertion: func(t *testing.T, err error, api *mock.MockApi, output string) {
+				require.NoError(t, err)
+
+				t.Log(output)
+				lines := strings.Split(output, "\n")
+				require.True(t, strings.HasPrefix(lines[1], "Go"))
+				require.True(t, strings.HasSuffix(lines[1], "\t      -\t"))
+				require.False(t, strings.Contains(output, "NaN"))
+			},
+			tearDown: func() {
+				mockApi.InitMock()
+			},
+		},
+		{
+			name:     "OK with token",
+			commands: []string{"", "languages"},
+			setup: func() {
+				c.ExportSetToken("ghq_foobartoken")
+				mockApi.ListRepos = repositories
+				mockApi.ListLanguages = map[string]map[string]int{}
+			},
+			assertion: func(t *testing.T, err error, api *mock.MockApi, output string) {
+				require.NoError(t, err)
+				require.False(t, api.PublicCalled)
+				require.True(t, api.AuthenticatedCalled)
+				require.True(t, api.LanguagesCalled)
+			},
+			tearDown: func() {
+				mockApi.InitMock()
+				c.ExportInit()
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			tc.setup()
+			defer tc.tearDown()
+
+			// Prepare for standard output testing
+			stdOut := os.Stdout
+			r, w, _ := os.Pipe()
+			os.Stdout = w
+
+			// Act
+			err := app.Run(tc.commands)
+
+			_ = w.Close()
+			result, _ := io.ReadAll(r)
+			output := string(result)
+			os.Stdout = stdOut
+
+			// Assert
+			tc.assertion(t, err, mockApi, output)
+		})
+	}
+}
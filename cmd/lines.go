@@ -2,8 +2,8 @@ package cmd
 
 import (
 	"fmt"
+	"os"
 
-	"github.com/kokoichi206/go-git-stats/api"
 	"github.com/urfave/cli/v2"
 )
 
@@ -15,6 +15,13 @@ func (c *Cmd) LinesCommand() *cli.Command {
 		Description: "Get lines of codes you write before",
 		Flags: []cli.Flag{
 			&cli.StringFlag{Name: "name", Aliases: []string{"n"}},
+			&cli.BoolFlag{Name: "detail", Aliases: []string{"d"}, Usage: "show lines of codes of each repository"},
+			&cli.BoolFlag{Name: "by-language", Usage: "estimate lines of codes per language"},
+			&cli.StringFlag{
+				Name:  "language-mode",
+				Value: languageModeBytes,
+				Usage: fmt.Sprintf("how to attribute lines to languages (%s, %s)", languageModeBytes, languageModePrimary),
+			},
 		},
 		Action: c.getLinesOfCodes,
 	}
@@ -23,25 +30,13 @@ func (c *Cmd) LinesCommand() *cli.Command {
 // Get lines of codes you write before.
 func (c *Cmd) getLinesOfCodes(cc *cli.Context) error {
 
-	var repositories []api.Repository
-	var err error
-
-	// With Github access token
-	token := c.config.Token
-	if token != "" {
-		repositories, err = c.api.ListRepositoriesForAuthenticatedUser()
-		if err != nil {
-			return err
-		}
+	repositories, err := c.listRepositories(cc.String("name"))
+	if err != nil {
+		return err
 	}
 
-	// With username
-	userName := cc.String("name")
-	if userName != "" {
-		repositories, err = c.api.ListPublicRepositories(userName)
-		if err != nil {
-			return err
-		}
+	if cc.Bool("by-language") {
+		return c.getLinesByLanguage(repositories, cc.String("language-mode"))
 	}
 
 	c.wait.Add(len(repositories))
@@ -49,8 +44,19 @@ func (c *Cmd) getLinesOfCodes(cc *cli.Context) error {
 		go c.WeeklyCommitActivityAsyncCall(repository.FullName)
 	}
 	c.wait.Wait()
+	if err := c.checkFailures(os.Stderr); err != nil {
+		return err
+	}
 
 	// Final output
+	if cc.Bool("detail") {
+		return c.printLinesReport("Repository", c.rows)
+	}
+	if c.jsonOutput() {
+		return printJSON(struct {
+			Total int `json:"total"`
+		}{c.total})
+	}
 	fmt.Println(c.total)
 	return nil
 }
@@ -60,22 +66,23 @@ func (c *Cmd) WeeklyCommitActivityAsyncCall(fullName string) {
 
 	// Always decrements the WaitGroup counter.
 	defer c.wait.Done()
+	c.acquire()
+	defer c.release()
 
 	// Call function
 	stats, err := c.api.WeeklyCommitActivity(fullName)
 	if err != nil {
+		c.fail(fullName, err)
 		return
 	}
 
 	// Calculate lines of codes of a specific repository.
-	total := 0
-	for _, s := range stats {
-		total += s.Additions + s.Deletions
-	}
+	r := sumLines(fullName, stats)
 
 	// Add to total lines of codes.
 	c.mutex.Lock()
-	c.total += total
+	c.total += r.lines()
+	c.rows = append(c.rows, r)
 	c.mutex.Unlock()
 
 	return
@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/kokoichi206/go-git-stats/api"
+)
+
+const (
+	// Split lines of a repository across its languages in proportion to their byte counts.
+	languageModeBytes = "bytes"
+	// Attribute all lines of a repository to its primary language.
+	languageModePrimary = "primary"
+
+	// Language name used when a repository has no detected language.
+	unknownLanguage = "Unknown"
+)
+
+// Estimated lines of codes of a language in JSON.
+type languageLinesJSON struct {
+	Name  string `json:"name"`
+	Lines int    `json:"lines"`
+}
+
+// Estimate lines of codes per language and print them as a table.
+func (c *Cmd) getLinesByLanguage(repositories []api.Repository, mode string) error {
+
+	if mode != languageModeBytes && mode != languageModePrimary {
+		return fmt.Errorf("language-mode must be %s or %s, but got '%s'.", languageModeBytes, languageModePrimary, mode)
+	}
+
+	perLanguage := make(map[string]float64)
+
+	c.wait.Add(len(repositories))
+	for _, repository := range repositories {
+		go c.linesByLanguageAsyncCall(repository, mode, perLanguage)
+	}
+	c.wait.Wait()
+	if err := c.checkFailures(os.Stderr); err != nil {
+		return err
+	}
+
+	sorted := make([]languageCount, 0, len(perLanguage))
+	for name, lines := range perLanguage {
+		sorted = append(sorted, languageCount{name: name, count: int(lines + 0.5)})
+	}
+	sortByCount(sorted)
+
+	if c.jsonOutput() {
+		report := struct {
+			Mode      string              `json:"mode"`
+			Languages []languageLinesJSON `json:"languages"`
+			Total     int                 `json:"total"`
+		}{Mode: mode, Languages: make([]languageLinesJSON, 0, len(sorted)), Total: c.total}
+		for _, l := range sorted {
+			report.Languages = append(report.Languages, languageLinesJSON{Name: l.name, Lines: l.count})
+		}
+		return printJSON(report)
+	}
+
+	// Header explaining how the numbers are estimated.
+	switch mode {
+	case languageModeBytes:
+		fmt.Println("# Estimated lines per language: lines (additions + deletions) of each repository")
+		fmt.Println("# are split across its languages in proportion to their byte counts.")
+	case languageModePrimary:
+		fmt.Println("# Estimated lines per language: all lines (additions + deletions) of each repository")
+		fmt.Println("# are attributed to its primary language.")
+	}
+
+	fmt.Printf("%-20s\t%12s\t%7s\n", "Language", "Lines", "Percent")
+	for _, l := range sorted {
+		fmt.Printf("%-20s\t%12d\t%s\n", l.name, l.count, ratio(l.count, c.total))
+	}
+	fmt.Printf("%-20s\t%12d\n", "Total", c.total)
+	return nil
+}
+
+// Asynchronous API calls and attribute lines of codes of a repository to its languages.
+func (c *Cmd) linesByLanguageAsyncCall(repository api.Repository, mode string, perLanguage map[string]float64) {
+
+	// Always decrements the WaitGroup counter.
+	defer c.wait.Done()
+	c.acquire()
+	defer c.release()
+
+	stats, err := c.api.WeeklyCommitActivity(repository.FullName)
+	if err != nil {
+		c.fail(repository.FullName, err)
+		return
+	}
+
+	total := sumLines(repository.FullName, stats).lines()
+
+	shares := map[string]float64{}
+	if mode == languageModeBytes {
+		languages, err := c.api.Languages(repository.FullName)
+		// Backends without bytes of languages have the primary language only.
+		if errors.Is(err, api.ErrNotSupported) && repository.Language != "" {
+			languages, err = map[string]int{repository.Language: 1}, nil
+		}
+		if err != nil {
+			c.fail(repository.FullName, err)
+			return
+		}
+		sum := 0
+		for _, bytes := range languages {
+			sum += bytes
+		}
+		for name, bytes := range languages {
+			if sum > 0 {
+				shares[name] = float64(bytes) / float64(sum)
+			}
+		}
+	} else if repository.Language != "" {
+		shares[repository.Language] = 1
+	}
+
+	if len(shares) == 0 {
+		shares[unknownLanguage] = 1
+	}
+
+	c.mutex.Lock()
+	c.total += total
+	for name, share := range shares {
+		perLanguage[name] += float64(total) * share
+	}
+	c.mutex.Unlock()
+}
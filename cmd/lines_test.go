@@ -136,6 +136,48 @@ func TestLinesCommand(t *testing.T) {
 				c.ExportInit()
 			},
 		},
+		{
+			name:     "OK with detail",
+			commands: []string{"", "lines", "-n", "kokoichi206", "-d"},
+			setup: func() {
+				mockApi.ListRepos = []api.Repository{
+					{
+						ID:       489517307,
+						Private:  false,
+						Name:     "account-book-api",
+						FullName: "kokoichi206/account-book-api",
+					},
+				}
+				mockApi.ListCodeFreq = [][]api.CodeFrequency{
+					{
+						{
+							Time:      1659830400,
+							Additions: 500,
+							Deletions: -100,
+						},
+						{
+							Time:      1659225600,
+							Additions: 20,
+							Deletions: -5,
+						},
+					},
+				}
+			},
+			assertion: func(t *testing.T, err error, api *mock.MockApi, output string) {
+				require.NoError(t, err)
+				require.Equal(t, 415, c.ExportGetTotal())
+
+				t.Log(output)
+				lines := strings.Split(output, "\n")
+				require.Equal(t, []string{"Repository", "Additions", "Deletions", "Lines"}, strings.Fields(lines[0]))
+				require.Equal(t, []string{"kokoichi206/account-book-api", "520", "-105", "415"}, strings.Fields(lines[1]))
+				require.Equal(t, []string{"Total", "520", "-105", "415"}, strings.Fields(lines[2]))
+			},
+			tearDown: func() {
+				mockApi.InitMock()
+				c.ExportInit()
+			},
+		},
 		{
 			name:     "Without token and username",
 			commands: []string{"", "lines"},
@@ -184,3 +226,209 @@ func TestLinesCommand(t *testing.T) {
 		})
 	}
 }
+
+func TestLinesByLanguage(t *testing.T) {
+
+	config, _ := util.LoadConfig()
+	mockApi := mock.New(config)
+
+	c := cmd.ExportNewCommandWithMock(config, mockApi)
+
+	app := cli.NewApp()
+	app.Commands = c.NewCommands()
+
+	testCases := []struct {
+		name      string
+		commands  []string
+		setup     func()
+		assertion func(t *testing.T, err error, api *mock.MockApi, output string)
+		tearDown  func()
+	}{
+		{
+			name:     "OK split by bytes",
+			commands: []string{"", "lines", "-n", "kokoichi206", "--by-language"},
+			setup: func() {
+				mockApi.ListRepos = []api.Repository{
+					{
+						ID:       489517307,
+						Name:     "account-book-api",
+						FullName: "kokoichi206/account-book-api",
+						Language: "Go",
+					},
+				}
+				mockApi.ListCodeFreq = [][]api.CodeFrequency{
+					{
+						{
+							Time:      1659830400,
+							Additions: 900,
+							Deletions: 100,
+						},
+					},
+				}
+				mockApi.ListLanguages = map[string]map[string]int{
+					"kokoichi206/account-book-api": {"Go": 3000, "Shell": 1000},
+				}
+			},
+			assertion: func(t *testing.T, err error, api *mock.MockApi, output string) {
+				require.NoError(t, err)
+				require.True(t, api.WeeklyCodeCalled)
+				require.True(t, api.LanguagesCalled)
+				require.Equal(t, 1000, c.ExportGetTotal())
+
+				t.Log(output)
+				lines := strings.Split(output, "\n")
+				// Estimation is documented in the header
+				require.True(t, strings.HasPrefix(lines[0], "# Estimated lines per language"))
+				require.True(t, strings.Contains(output, "byte counts"))
+				require.True(t, strings.HasPrefix(lines[3], "Go"))
+				require.True(t, strings.Contains(lines[3], "750"))
+				require.True(t, strings.Contains(lines[3], "75.0%"))
+				require.True(t, strings.HasPrefix(lines[4], "Shell"))
+				require.True(t, strings.Contains(lines[4], "250"))
+				require.True(t, strings.HasPrefix(lines[5], "Total"))
+				require.True(t, strings.Contains(lines[5], "1000"))
+			},
+			tearDown: func() {
+				mockApi.InitMock()
+				c.ExportInit()
+			},
+		},
+		{
+			name:     "OK primary language",
+			commands: []string{"", "lines", "-n", "kokoichi206", "--by-language", "--language-mode", "primary"},
+			setup: func() {
+				mockApi.ListRepos = []api.Repository{
+					{
+						ID:       489517307,
+						Name:     "account-book-api",
+						FullName: "kokoichi206/account-book-api",
+						Language: "Go",
+					},
+					{
+						ID:       429817377,
+						Name:     "utils",
+						FullName: "kokoichi206/utils",
+					},
+				}
+				mockApi.ListCodeFreq = [][]api.CodeFrequency{
+					{
+						{
+							Time:      1659830400,
+							Additions: 300,
+							Deletions: 0,
+						},
+					},
+					{
+						{
+							Time:      1659830400,
+							Additions: 300,
+							Deletions: 0,
+						},
+					},
+				}
+			},
+			assertion: func(t *testing.T, err error, api *mock.MockApi, output string) {
+				require.NoError(t, err)
+				require.True(t, api.WeeklyCodeCalled)
+				require.False(t, api.LanguagesCalled)
+				require.Equal(t, 600, c.ExportGetTotal())
+
+				t.Log(output)
+				require.True(t, strings.Contains(output, "primary language"))
+				require.True(t, strings.Contains(output, "Go"))
+				// Repository without language
+				require.True(t, strings.Contains(output, "Unknown"))
+				require.True(t, strings.Contains(output, "50.0%"))
+			},
+			tearDown: func() {
+				mockApi.InitMock()
+				c.ExportInit()
+			},
+		},
+		{
+			name:     "Invalid language mode",
+			commands: []string{"", "lines", "-n", "kokoichi206", "--by-language", "--language-mode", "files"},
+			setup: func() {
+				mockApi.ListRepos = nil
+			},
+			assertion: func(t *testing.T, err error, api *mock.MockApi, output string) {
+				require.Error(t, err)
+				require.False(t, api.WeeklyCodeCalled)
+			},
+			tearDown: func() {
+				mockApi.InitMock()
+				c.ExportInit()
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			tc.setup()
+			defer tc.tearDown()
+
+			// Prepare for standard output testing
+			stdOut := os.Stdout
+			r, w, _ := os.Pipe()
+			os.Stdout = w
+
+			// Act
+			err := app.Run(tc.commands)
+
+			_ = w.Close()
+			result, _ := io.ReadAll(r)
+			output := string(result)
+			os.Stdout = stdOut
+
+			// Assert
+			tc.assertion(t, err, mockApi, output)
+		})
+	}
+}
+
+func TestLinesCommandJSON(t *testing.T) {
+
+	// Arrange
+	config := util.Config{
+		Backend:     util.BackendGitHub,
+		Output:      util.OutputJSON,
+		Concurrency: 1,
+	}
+	mockApi := mock.New(config)
+	mockApi.ListRepos = []api.Repository{
+		{ID: 489517307, Name: "account-book-api", FullName: "kokoichi206/account-book-api"},
+		{ID: 429817377, Name: "utils", FullName: "kokoichi206/utils"},
+	}
+	mockApi.ListCodeFreq = [][]api.CodeFrequency{
+		{{Time: 1659830400, Additions: 300, Deletions: -100}},
+		{{Time: 1659830400, Additions: 300, Deletions: -100}},
+	}
+
+	c := cmd.New(config, mockApi)
+	app := cli.NewApp()
+	app.Commands = c.NewCommands()
+
+	stdOut := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	// Act
+	err := app.Run([]string{"", "lines", "-n", "kokoichi206", "-d"})
+
+	_ = w.Close()
+	result, _ := io.ReadAll(r)
+	os.Stdout = stdOut
+
+	// Assert
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"rows": [
+			{"name": "kokoichi206/account-book-api", "additions": 300, "deletions": -100, "lines": 200},
+			{"name": "kokoichi206/utils", "additions": 300, "deletions": -100, "lines": 200}
+		],
+		"total": {"name": "Total", "additions": 600, "deletions": -200, "lines": 400}
+	}`, string(result))
+}
@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/kokoichi206/go-git-stats/api"
+	"github.com/kokoichi206/go-git-stats/api/local"
+	"github.com/urfave/cli/v2"
+)
+
+// Layout of since and until flags.
+const dateLayout = "2006-01-02"
+
+// Return cli command about local git repositories.
+func (c *Cmd) LocalCommand() *cli.Command {
+	return &cli.Command{
+		Name:        "local",
+		ArgsUsage:   "[directory]",
+		Description: "Get lines of codes of every git repository under a directory",
+		Flags: []cli.Flag{
+			&cli.StringSliceFlag{Name: "author", Aliases: []string{"a"}, Usage: "author names, emails or GitHub logins (all authors if not given)"},
+			&cli.BoolFlag{Name: "by-author", Usage: "show lines of codes of each author instead of each repository"},
+			&cli.StringFlag{Name: "since", Usage: "count commits on or after the date (YYYY-MM-DD)"},
+			&cli.StringFlag{Name: "until", Usage: "count commits on or before the date (YYYY-MM-DD)"},
+		},
+		Action: c.getLocalLinesOfCodes,
+	}
+}
+
+// Get lines of codes of local git repositories.
+// The directory defaults to the path of the local backend.
+func (c *Cmd) getLocalLinesOfCodes(cc *cli.Context) error {
+
+	dir := cc.Args().First()
+	if dir == "" {
+		dir = c.config.LocalPath
+	}
+	if dir == "" {
+		dir = "."
+	}
+
+	filter := local.Filter{
+		Authors: cc.StringSlice("author"),
+	}
+	if since := cc.String("since"); since != "" {
+		t, err := time.Parse(dateLayout, since)
+		if err != nil {
+			return fmt.Errorf("since must be YYYY-MM-DD: %w", err)
+		}
+		filter.Since = t
+	}
+	if until := cc.String("until"); until != "" {
+		t, err := time.Parse(dateLayout, until)
+		if err != nil {
+			return fmt.Errorf("until must be YYYY-MM-DD: %w", err)
+		}
+		// Include the whole day.
+		filter.Until = t.AddDate(0, 0, 1)
+	}
+
+	// Directories which can not be read are reported with the repositories which fail.
+	paths, err := local.Discover(dir, c.fail)
+	if err != nil {
+		return err
+	}
+
+	byAuthor := cc.Bool("by-author")
+	c.wait.Add(len(paths))
+	for _, path := range paths {
+		go c.localLinesAsyncCall(path, filter, byAuthor)
+	}
+	c.wait.Wait()
+	if err := c.checkFailures(os.Stderr); err != nil {
+		return err
+	}
+
+	if byAuthor {
+		return c.printLinesReport("Author", mergeRows(c.rows))
+	}
+	return c.printLinesReport("Repository", c.rows)
+}
+
+// Asynchronous git log call and calculate lines of codes of a local repository.
+// Lines of codes are summed up per author if byAuthor is true.
+func (c *Cmd) localLinesAsyncCall(path string, filter local.Filter, byAuthor bool) {
+
+	// Always decrements the WaitGroup counter.
+	defer c.wait.Done()
+	c.acquire()
+	defer c.release()
+
+	stats, err := local.CommitStats(path, c.config.Identities, filter)
+	if err != nil {
+		c.fail(path, err)
+		return
+	}
+
+	perAuthor := map[string][]api.CommitStat{}
+	for _, s := range stats {
+		key := path
+		if byAuthor {
+			key = s.Author
+		}
+		perAuthor[key] = append(perAuthor[key], s)
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if !byAuthor && len(stats) == 0 {
+		// Show repositories without any commits as well.
+		c.rows = append(c.rows, repositoryLines{fullName: path})
+	}
+	for key, s := range perAuthor {
+		r := sumLines(key, api.WeeklyCodeFrequency(s))
+		c.total += r.lines()
+		c.rows = append(c.rows, r)
+	}
+}
@@ -0,0 +1,229 @@
+package cmd_test
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kokoichi206/go-git-stats/api/mock"
+	"github.com/kokoichi206/go-git-stats/cmd"
+	"github.com/kokoichi206/go-git-stats/util"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v2"
+)
+
+// Commit the file to the git repository at dir (initialized if needed).
+func gitCommit(t *testing.T, dir, file, content, author string, at time.Time) {
+	t.Helper()
+
+	git := func(args ...string) {
+		cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+		date := at.Format(time.RFC3339)
+		cmd.Env = append(os.Environ(), "GIT_AUTHOR_DATE="+date, "GIT_COMMITTER_DATE="+date, "GIT_CONFIG_NOSYSTEM=1", "HOME="+dir)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v: %s", args, err, out)
+		}
+	}
+
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, filepath.Dir(file)), 0o755))
+	if _, err := os.Stat(filepath.Join(dir, ".git")); err != nil {
+		git("init", "-q")
+	}
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, file), []byte(content), 0o644))
+	git("add", "-A")
+	git("-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "-m", file, "--author", author+" <"+author+">")
+}
+
+func TestLocalCommand(t *testing.T) {
+
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not found")
+	}
+
+	root := t.TempDir()
+	day := time.Date(2022, 8, 10, 12, 0, 0, 0, time.UTC)
+	gitCommit(t, filepath.Join(root, "work", "api"), "main.go", "1\n2\n3\n", "me@work.example.com", day)
+	gitCommit(t, filepath.Join(root, "work", "api"), "main.go", "1\n", "someone@example.com", day.AddDate(0, 0, 1))
+	gitCommit(t, filepath.Join(root, "personal", "tool"), "a.py", "1\n2\n", "me@personal.example.com", day.AddDate(0, -1, 0))
+	// Repositories in node_modules are not searched
+	gitCommit(t, filepath.Join(root, "work", "api", "node_modules", "lib"), "index.js", "1\n2\n3\n4\n", "me@work.example.com", day)
+	// Repository without any commits
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "empty"), 0o755))
+	require.NoError(t, exec.Command("git", "-C", filepath.Join(root, "empty"), "init", "-q").Run())
+
+	config, _ := util.LoadConfig()
+	mockApi := mock.New(config)
+
+	c := cmd.ExportNewCommandWithMock(config, mockApi)
+
+	app := cli.NewApp()
+	app.Commands = c.NewCommands()
+
+	testCases := []struct {
+		name      string
+		commands  []string
+		assertion func(t *testing.T, err error, output string)
+	}{
+		{
+			name:     "OK all authors",
+			commands: []string{"", "local", root},
+			assertion: func(t *testing.T, err error, output string) {
+				require.NoError(t, err)
+				require.False(t, strings.Contains(output, "node_modules"))
+
+				lines := strings.Split(output, "\n")
+				require.True(t, strings.HasPrefix(lines[1], filepath.Join(root, "empty")))
+				require.Equal(t, []string{"0", "0", "0"}, strings.Fields(lines[1])[1:])
+				require.True(t, strings.HasPrefix(lines[2], filepath.Join(root, "personal", "tool")))
+				require.Equal(t, []string{"2", "0", "2"}, strings.Fields(lines[2])[1:])
+				require.True(t, strings.HasPrefix(lines[3], filepath.Join(root, "work", "api")))
+				require.Equal(t, []string{"3", "-2", "1"}, strings.Fields(lines[3])[1:])
+				require.Equal(t, []string{"Total", "5", "-2", "3"}, strings.Fields(lines[4]))
+				require.Equal(t, 3, c.ExportGetTotal())
+			},
+		},
+		{
+			name:     "OK with authors",
+			commands: []string{"", "local", "-a", "ME@work.example.com", "-a", "me@personal.example.com", root},
+			assertion: func(t *testing.T, err error, output string) {
+				require.NoError(t, err)
+				require.True(t, strings.Contains(output, "Total"))
+				require.Equal(t, 5, c.ExportGetTotal())
+			},
+		},
+		{
+			name:     "OK with time range",
+			commands: []string{"", "local", "--since", "2022-08-01", "--until", "2022-08-10", root},
+			assertion: func(t *testing.T, err error, output string) {
+				require.NoError(t, err)
+				// Only the first commit of work/api
+				require.Equal(t, 3, c.ExportGetTotal())
+			},
+		},
+		{
+			name:     "Invalid date",
+			commands: []string{"", "local", "--since", "2022/08/01", root},
+			assertion: func(t *testing.T, err error, output string) {
+				require.Error(t, err)
+				require.True(t, strings.Contains(err.Error(), "YYYY-MM-DD"))
+			},
+		},
+		{
+			name:     "Directory not found",
+			commands: []string{"", "local", filepath.Join(root, "not-found")},
+			assertion: func(t *testing.T, err error, output string) {
+				require.Error(t, err)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			defer c.ExportInit()
+
+			// Prepare for standard output testing
+			stdOut := os.Stdout
+			r, w, _ := os.Pipe()
+			os.Stdout = w
+
+			// Act
+			err := app.Run(tc.commands)
+
+			_ = w.Close()
+			result, _ := io.ReadAll(r)
+			output := string(result)
+			os.Stdout = stdOut
+
+			// Assert
+			t.Log(output)
+			tc.assertion(t, err, output)
+		})
+	}
+}
+
+func TestLocalCommandByAuthor(t *testing.T) {
+
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not found")
+	}
+
+	root := t.TempDir()
+	day := time.Date(2022, 8, 10, 12, 0, 0, 0, time.UTC)
+	gitCommit(t, filepath.Join(root, "work"), "main.go", "1\n2\n3\n", "me@work.example.com", day)
+	gitCommit(t, filepath.Join(root, "work"), "util.go", "1\n", "someone@example.com", day)
+	gitCommit(t, filepath.Join(root, "personal"), "a.py", "1\n2\n", "me@example.com", day)
+
+	config, _ := util.LoadConfig()
+	config.Identities = util.Identities{
+		People: []util.Person{
+			{Name: "me", Emails: []string{"me@example.com", "me@work.example.com"}},
+		},
+	}
+	mockApi := mock.New(config)
+
+	c := cmd.ExportNewCommandWithMock(config, mockApi)
+
+	app := cli.NewApp()
+	app.Commands = c.NewCommands()
+
+	testCases := []struct {
+		name      string
+		commands  []string
+		assertion func(t *testing.T, err error, output string)
+	}{
+		{
+			name:     "OK by author",
+			commands: []string{"", "local", "--by-author", root},
+			assertion: func(t *testing.T, err error, output string) {
+				require.NoError(t, err)
+
+				lines := strings.Split(output, "\n")
+				require.Equal(t, "Author", strings.Fields(lines[0])[0])
+				// Emails of the same person are merged.
+				require.Equal(t, []string{"me", "5", "0", "5"}, strings.Fields(lines[1]))
+				require.Equal(t, []string{"someone@example.com", "1", "0", "1"}, strings.Fields(lines[2]))
+				require.Equal(t, []string{"Total", "6", "0", "6"}, strings.Fields(lines[3]))
+			},
+		},
+		{
+			name:     "OK author by canonical name",
+			commands: []string{"", "local", "-a", "me", root},
+			assertion: func(t *testing.T, err error, output string) {
+				require.NoError(t, err)
+				require.Equal(t, 5, c.ExportGetTotal())
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			defer c.ExportInit()
+
+			// Prepare for standard output testing
+			stdOut := os.Stdout
+			r, w, _ := os.Pipe()
+			os.Stdout = w
+
+			// Act
+			err := app.Run(tc.commands)
+
+			_ = w.Close()
+			result, _ := io.ReadAll(r)
+			output := string(result)
+			os.Stdout = stdOut
+
+			// Assert
+			t.Log(output)
+			tc.assertion(t, err, output)
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package cmd_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+// Isolate the tests from the tokens and the config files of the user,
+// since the token is looked up in the environment variables, gh and git credential helpers.
+func TestMain(m *testing.M) {
+
+	home, err := ioutil.TempDir("", "ggs-test-home")
+	if err != nil {
+		fmt.Println("failed to ioutil.TempDir:", err)
+		os.Exit(1)
+	}
+
+	for _, key := range []string{"GGS_TOKEN", "GGS_TOKENS", "GH_TOKEN", "GITHUB_TOKEN", "GH_ENTERPRISE_TOKEN", "GITHUB_ENTERPRISE_TOKEN", "GGS_PASSPHRASE", "GGS_KEY_FILE", "GGS_HOST"} {
+		os.Unsetenv(key)
+	}
+	for key, value := range map[string]string{
+		"HOME":                home,
+		"XDG_CONFIG_HOME":     home,
+		"GH_CONFIG_DIR":       home,
+		"GIT_CONFIG_NOSYSTEM": "1",
+		"GIT_CONFIG_GLOBAL":   os.DevNull,
+		// An empty helper clears the helpers of the repository as well, so 'git credential fill' finds nothing.
+		"GIT_CONFIG_COUNT":   "1",
+		"GIT_CONFIG_KEY_0":   "credential.helper",
+		"GIT_CONFIG_VALUE_0": "",
+	} {
+		os.Setenv(key, value)
+	}
+
+	code := m.Run()
+	os.RemoveAll(home)
+	os.Exit(code)
+}
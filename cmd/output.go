@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/kokoichi206/go-git-stats/util"
+)
+
+// Lines of codes of a row of the report in JSON.
+type linesJSON struct {
+	Name      string `json:"name"`
+	Additions int    `json:"additions"`
+	Deletions int    `json:"deletions"`
+	Lines     int    `json:"lines"`
+}
+
+// Returns whether the output format is JSON.
+func (c *Cmd) jsonOutput() bool {
+	return c.config.Output == util.OutputJSON
+}
+
+// Print the value as indented JSON.
+func printJSON(v interface{}) error {
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+// Lines of codes of the row in JSON.
+func (r repositoryLines) json() linesJSON {
+	return linesJSON{Name: r.fullName, Additions: r.additions, Deletions: r.deletions, Lines: r.lines()}
+}
@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/kokoichi206/go-git-stats/api"
+	"github.com/urfave/cli/v2"
+)
+
+// Current time, which is replaced in tests.
+var now = time.Now
+
+// Return cli command about participation.
+func (c *Cmd) ParticipationCommand() *cli.Command {
+	return &cli.Command{
+		Name:        "participation",
+		Aliases:     []string{"p"},
+		Description: "Get owner commits vs all commits of a specific repository over the last 52 weeks",
+		Flags: []cli.Flag{
+			&cli.StringFlag{Name: "name", Aliases: []string{"n"}},
+		},
+		Action: c.getParticipation,
+	}
+}
+
+// Get the weekly commit counts of a specific repository,
+// and show how much of the activity comes from external contributors.
+func (c *Cmd) getParticipation(cc *cli.Context) error {
+	// get fullName (<userName>/<repo>)
+	fullName := cc.String("name")
+	if fullName == "" {
+		// not correct usage
+		return errors.New("name flag is not given.")
+	}
+
+	p, err := c.api.Participation(fullName)
+	if err != nil {
+		return err
+	}
+
+	// The last element is the week until today.
+	today := now()
+
+	totalOwner := 0
+	totalAll := 0
+	weeks := make([]participationJSON, 0, len(p.All))
+	if !c.jsonOutput() {
+		fmt.Printf("%-10s\t%10s\t%10s\t%8s\n", "Week", "Owner", "All", "Ratio")
+	}
+	for i, all := range p.All {
+		owner := 0
+		if i < len(p.Owner) {
+			owner = p.Owner[i]
+		}
+		totalOwner += owner
+		totalAll += all
+
+		week := api.ParticipationWeekStart(today, i, len(p.All))
+		weeks = append(weeks, participationJSON{Week: week.Format("2006-01-02"), Owner: owner, All: all})
+		if !c.jsonOutput() {
+			fmt.Printf("%-10s\t%10d\t%10d\t%8s\n", week.Format("2006-01-02"), owner, all, ratio(owner, all))
+		}
+	}
+
+	if c.jsonOutput() {
+		return printJSON(struct {
+			Weeks    []participationJSON `json:"weeks"`
+			Owner    int                 `json:"owner"`
+			External int                 `json:"external"`
+			All      int                 `json:"all"`
+		}{weeks, totalOwner, totalAll - totalOwner, totalAll})
+	}
+
+	fmt.Println()
+	fmt.Printf("Owner commits:     %d\n", totalOwner)
+	fmt.Printf("External commits:  %d\n", totalAll-totalOwner)
+	fmt.Printf("All commits:       %d\n", totalAll)
+	fmt.Printf("External share:    %s\n", ratio(totalAll-totalOwner, totalAll))
+	return nil
+}
+
+// Weekly commit counts in JSON.
+type participationJSON struct {
+	Week  string `json:"week"`
+	Owner int    `json:"owner"`
+	All   int    `json:"all"`
+}
+
+// Format part/whole as a percentage.
+func ratio(part, whole int) string {
+	if whole == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.1f%%", float64(part)*100/float64(whole))
+}
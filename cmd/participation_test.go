@@ -0,0 +1,140 @@
+package cmd_test
+
+import (
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kokoichi206/go-git-stats/api"
+	"github.com/kokoichi206/go-git-stats/api/mock"
+	"github.com/kokoichi206/go-git-stats/cmd"
+	"github.com/kokoichi206/go-git-stats/util"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v2"
+)
+
+func TestParticipationCommand(t *testing.T) {
+
+	config, _ := util.LoadConfig()
+	mockApi := mock.New(config)
+	// Wednesday
+	defer cmd.ExportSetNow(time.Date(2022, 8, 10, 12, 0, 0, 0, time.UTC))()
+
+	c := cmd.ExportNewCommandWithMock(config, mockApi)
+
+	app := cli.NewApp()
+	app.Commands = c.NewCommands()
+
+	testCases := []struct {
+		name      string
+		commands  []string
+		setup     func()
+		assertion func(t *testing.T, err error, api *mock.MockApi, output string)
+		tearDown  func()
+	}{
+		{
+			name:     "OK",
+			commands: []string{"", "participation", "-name", "kokoichi206/go-git-stats"},
+			setup: func() {
+				mockApi.ParticipationData = api.Participation{
+					All:   []int{10, 0, 30},
+					Owner: []int{5, 0, 15},
+				}
+			},
+			assertion: func(t *testing.T, err error, api *mock.MockApi, output string) {
+				require.NoError(t, err)
+				require.True(t, api.ParticipationCalled)
+				require.Equal(t, "kokoichi206/go-git-stats", api.PassedFullName)
+
+				t.Log(output)
+				// Ratio of each week
+				require.True(t, strings.Contains(output, "50.0%"))
+				// Weeks of 7 days until today (2022-08-10)
+				require.True(t, strings.Contains(output, "2022-07-20\t         5\t        10\t   50.0%"))
+				require.True(t, strings.Contains(output, "2022-07-27\t         0\t         0\t       -"))
+				require.True(t, strings.Contains(output, "2022-08-03\t        15\t        30\t   50.0%"))
+				// Summary
+				require.True(t, strings.Contains(output, "Owner commits:     20"))
+				require.True(t, strings.Contains(output, "External commits:  20"))
+				require.True(t, strings.Contains(output, "All commits:       40"))
+				require.True(t, strings.Contains(output, "External share:    50.0%"))
+			},
+			tearDown: func() {
+				mockApi.InitMock()
+			},
+		},
+		{
+			name:     "abbr of subcommand",
+			commands: []string{"", "p", "-n", "kokoichi206/go-git-stats"},
+			setup: func() {
+				mockApi.ParticipationData = api.Participation{
+					All:   []int{4},
+					Owner: []int{1},
+				}
+			},
+			assertion: func(t *testing.T, err error, api *mock.MockApi, output string) {
+				require.NoError(t, err)
+				require.True(t, api.ParticipationCalled)
+				require.True(t, strings.Contains(output, "External share:    75.0%"))
+			},
+			tearDown: func() {
+				mockApi.InitMock()
+			},
+		},
+		{
+			name:     "No fullName",
+			commands: []string{"", "participation"},
+			setup:    func() {},
+			assertion: func(t *testing.T, err error, api *mock.MockApi, output string) {
+				require.Error(t, err)
+				require.False(t, api.ParticipationCalled)
+			},
+			tearDown: func() {
+				mockApi.InitMock()
+			},
+		},
+		{
+			name:     "API call error",
+			commands: []string{"", "participation", "-name", "kokoichi206/go-git-stats"},
+			setup: func() {
+				mockApi.Error = errors.New("mock Error: participation")
+			},
+			assertion: func(t *testing.T, err error, api *mock.MockApi, output string) {
+				require.Error(t, err)
+				require.True(t, api.ParticipationCalled)
+			},
+			tearDown: func() {
+				mockApi.InitMock()
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			tc.setup()
+			defer tc.tearDown()
+
+			// Prepare for standard output testing
+			stdOut := os.Stdout
+			r, w, _ := os.Pipe()
+			os.Stdout = w
+
+			// Act
+			err := app.Run(tc.commands)
+
+			_ = w.Close()
+			result, _ := io.ReadAll(r)
+			output := string(result)
+			os.Stdout = stdOut
+
+			// Assert
+			tc.assertion(t, err, mockApi, output)
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/kokoichi206/go-git-stats/api"
 	"github.com/urfave/cli/v2"
 )
 
@@ -24,34 +25,47 @@ func (c *Cmd) RepoCommand() *cli.Command {
 // 1. If the github access token is set to Config,
 //	 the target is all repositories (including private repos).
 // 2. If the github access token is NOT set to Config,
-//	 the target is public repositories (specify username as a "name" flag).
+//	 the target is public repositories (specify username as a "name" flag,
+//	 or the default user of the configurations).
 func (c *Cmd) getRepositories(cc *cli.Context) error {
 	// With Github access token
-	token := c.config.Token
-	if token != "" {
+	if c.authenticated() {
 		rs, err := c.api.ListRepositoriesForAuthenticatedUser()
 		if err != nil {
 			return err
 		}
-		for _, r := range rs {
-			fmt.Println(r)
-		}
-		return nil
+		return c.printRepositories(c.filterRepositories(rs))
 	}
 
 	// With username
 	useName := cc.String("name")
+	if useName == "" {
+		useName = c.config.User
+	}
 	if useName != "" {
 		rs, err := c.api.ListPublicRepositories(useName)
 		if err != nil {
 			return err
 		}
-		for _, r := range rs {
-			fmt.Println(r)
-		}
-		return nil
+		return c.printRepositories(c.filterRepositories(rs))
 	}
 
 	// not correct usage
 	return errors.New("Token or userName is not given.")
 }
+
+// Print the repositories in the output format.
+func (c *Cmd) printRepositories(rs []api.Repository) error {
+
+	if c.jsonOutput() {
+		if rs == nil {
+			rs = []api.Repository{}
+		}
+		return printJSON(rs)
+	}
+
+	for _, r := range rs {
+		fmt.Println(r)
+	}
+	return nil
+}
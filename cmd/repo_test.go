@@ -1,6 +1,8 @@
 package cmd_test
 
 import (
+	"io"
+	"os"
 	"testing"
 
 	"github.com/kokoichi206/go-git-stats/api"
@@ -122,6 +124,30 @@ func TestCommand(t *testing.T) {
 				c.ExportInit()
 			},
 		},
+		{
+			name:     "OK with local backend",
+			commands: []string{"", "repo"},
+			setup: func() {
+				c.ExportSetBackend(util.BackendLocal)
+				mockApi.ListRepos = []api.Repository{
+					{
+						ID:       1,
+						Private:  true,
+						Name:     "go-git-stats",
+						FullName: "/home/kokoichi206/src/go-git-stats",
+					},
+				}
+			},
+			assertion: func(t *testing.T, err error, api *mock.MockApi) {
+				require.NoError(t, err)
+				require.False(t, api.PublicCalled)
+				require.True(t, api.AuthenticatedCalled)
+			},
+			tearDown: func() {
+				mockApi.InitMock()
+				c.ExportInit()
+			},
+		},
 		{
 			name:     "Token or userName is not given",
 			commands: []string{"", "repo"},
@@ -164,3 +190,40 @@ func TestCommand(t *testing.T) {
 		})
 	}
 }
+
+func TestRepoCommandWithProfile(t *testing.T) {
+
+	// Arrange
+	config := util.Config{
+		Backend: util.BackendGitHub,
+		User:    "kokoichi206",
+		Filter:  util.RepositoryFilter{Exclude: []string{"*/utils"}},
+		Output:  util.OutputJSON,
+	}
+	mockApi := mock.New(config)
+	mockApi.ListRepos = []api.Repository{
+		{ID: 489517307, Name: "account-book-api", FullName: "kokoichi206/account-book-api", Language: "Go"},
+		{ID: 429817377, Name: "utils", FullName: "kokoichi206/utils"},
+	}
+
+	c := cmd.ExportNewCommandWithMock(config, mockApi)
+	app := cli.NewApp()
+	app.Commands = c.NewCommands()
+
+	stdOut := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	// Act
+	err := app.Run([]string{"", "repo"})
+
+	_ = w.Close()
+	result, _ := io.ReadAll(r)
+	os.Stdout = stdOut
+
+	// Assert
+	require.NoError(t, err)
+	// The default user is used without the name flag.
+	require.True(t, mockApi.PublicCalled)
+	require.JSONEq(t, `[{"id": 489517307, "private": false, "name": "account-book-api", "full_name": "kokoichi206/account-book-api", "language": "Go"}]`, string(result))
+}
@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/kokoichi206/go-git-stats/api"
+)
+
+// Lines of codes of a repository (or an author).
+type repositoryLines struct {
+	fullName  string
+	additions int
+	// Negative number like GitHub statistics.
+	deletions int
+}
+
+// Sum up lines of codes of the weekly statistics.
+func sumLines(fullName string, stats []api.CodeFrequency) repositoryLines {
+
+	r := repositoryLines{fullName: fullName}
+	for _, s := range stats {
+		r.additions += s.Additions
+		r.deletions += s.Deletions
+	}
+
+	return r
+}
+
+// Lines of codes which is the sum of additions and (negative) deletions.
+func (r repositoryLines) lines() int {
+	return r.additions + r.deletions
+}
+
+// Merge rows of the same name.
+func mergeRows(rows []repositoryLines) []repositoryLines {
+
+	indexes := map[string]int{}
+	var merged []repositoryLines
+	for _, r := range rows {
+		i, ok := indexes[r.fullName]
+		if !ok {
+			indexes[r.fullName] = len(merged)
+			merged = append(merged, r)
+			continue
+		}
+		merged[i].additions += r.additions
+		merged[i].deletions += r.deletions
+	}
+
+	return merged
+}
+
+// Print lines of codes of each row (repository or author) and their total.
+func (c *Cmd) printLinesReport(title string, rows []repositoryLines) error {
+
+	sort.Slice(rows, func(i, j int) bool {
+		return rows[i].fullName < rows[j].fullName
+	})
+
+	total := repositoryLines{fullName: "Total"}
+	for _, r := range rows {
+		total.additions += r.additions
+		total.deletions += r.deletions
+	}
+
+	if c.jsonOutput() {
+		report := struct {
+			Rows  []linesJSON `json:"rows"`
+			Total linesJSON   `json:"total"`
+		}{Rows: make([]linesJSON, 0, len(rows)), Total: total.json()}
+		for _, r := range rows {
+			report.Rows = append(report.Rows, r.json())
+		}
+		return printJSON(report)
+	}
+
+	fmt.Printf("%-40s\t%10s\t%10s\t%10s\n", title, "Additions", "Deletions", "Lines")
+	for _, r := range rows {
+		fmt.Printf("%-40s\t%10d\t%10d\t%10d\n", r.fullName, r.additions, r.deletions, r.lines())
+	}
+	fmt.Printf("%-40s\t%10d\t%10d\t%10d\n", total.fullName, total.additions, total.deletions, total.lines())
+	return nil
+}
@@ -34,13 +34,28 @@ func (c *Cmd) getStatistics(cc *cli.Context) error {
 
 	rs, err := c.api.WeeklyCommitActivity(fullName)
 	if err != nil {
-		fmt.Println(err)
 		return err
 	}
 
+	if c.jsonOutput() {
+		weeks := make([]weekJSON, 0, len(rs))
+		for _, r := range rs {
+			weeks = append(weeks, weekJSON{Week: time.Unix(int64(r.Time), 0).UTC(), Additions: r.Additions, Deletions: r.Deletions})
+		}
+		return printJSON(weeks)
+	}
+
 	fmt.Printf("%-30s\t%-10s\t%-5s\n", "Start Time", "Additions", "Deletions")
 	for _, r := range rs {
 		fmt.Printf("%s\t%10d\t%5d\n", time.Unix(int64(r.Time), 0), r.Additions, r.Deletions)
 	}
 	return nil
 }
+
+// Weekly statistics in JSON.
+type weekJSON struct {
+	// Start of the week.
+	Week      time.Time `json:"week"`
+	Additions int       `json:"additions"`
+	Deletions int       `json:"deletions"`
+}
@@ -0,0 +1,82 @@
+package cmd_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/kokoichi206/go-git-stats/api"
+	"github.com/kokoichi206/go-git-stats/api/mock"
+	"github.com/kokoichi206/go-git-stats/cmd"
+	"github.com/kokoichi206/go-git-stats/telemetry"
+	"github.com/kokoichi206/go-git-stats/util"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v2"
+)
+
+// Exporter which keeps what is exported.
+type memoryExporter struct {
+	mutex   sync.Mutex
+	spans   []telemetry.SpanData
+	metrics []telemetry.Metric
+}
+
+func (e *memoryExporter) ExportSpans(ctx context.Context, resource []telemetry.Attribute, spans []telemetry.SpanData) error {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	e.spans = append(e.spans, spans...)
+	return nil
+}
+
+func (e *memoryExporter) ExportMetrics(ctx context.Context, resource []telemetry.Attribute, metrics []telemetry.Metric) error {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	e.metrics = append(e.metrics, metrics...)
+	return nil
+}
+
+func TestCommandsTelemetry(t *testing.T) {
+
+	// Arrange
+	config := util.Config{Backend: util.BackendGitHub, ConfigPath: t.TempDir() + "/config.yml"}
+	mockApi := mock.New(config)
+	mockApi.ListRepos = []api.Repository{{Name: "utils", FullName: "kokoichi206/utils"}}
+
+	exporter := &memoryExporter{}
+	provider := telemetry.NewProvider(exporter)
+	c := cmd.ExportNewCommandWithMock(config, mockApi)
+	c.SetTelemetry(provider)
+	app := cli.NewApp()
+	app.Commands = c.NewCommands()
+
+	// Act
+	require.NoError(t, app.Run([]string{"", "repo", "-n", "kokoichi206"}))
+	require.NoError(t, app.Run([]string{"", "config", "path"}))
+	mockApi.Error = errors.New("failed to client.Do after several retries.")
+	require.Error(t, app.Run([]string{"", "stats", "-n", "kokoichi206/utils"}))
+	require.NoError(t, provider.Shutdown(context.Background()))
+
+	// Assert
+	require.Len(t, exporter.spans, 3)
+	require.Equal(t, "ggs repo", exporter.spans[0].Name)
+	require.Equal(t, []telemetry.Attribute{telemetry.String("ggs.command", "repo")}, exporter.spans[0].Attributes)
+	require.Empty(t, exporter.spans[0].Error)
+	require.Equal(t, "ggs config path", exporter.spans[1].Name)
+	require.Equal(t, "ggs stats", exporter.spans[2].Name)
+	require.Equal(t, "failed to client.Do after several retries.", exporter.spans[2].Error)
+	for _, s := range exporter.spans {
+		require.Empty(t, s.ParentSpanID)
+	}
+
+	require.Len(t, exporter.metrics, 2)
+	require.Equal(t, "ggs.command.runs", exporter.metrics[0].Name)
+	require.Equal(t, []telemetry.Point{
+		{Attributes: []telemetry.Attribute{telemetry.String("ggs.command", "repo"), telemetry.String("ggs.status", "ok")}, Value: 1},
+		{Attributes: []telemetry.Attribute{telemetry.String("ggs.command", "config path"), telemetry.String("ggs.status", "ok")}, Value: 1},
+		{Attributes: []telemetry.Attribute{telemetry.String("ggs.command", "stats"), telemetry.String("ggs.status", "error")}, Value: 1},
+	}, exporter.metrics[0].Points)
+	require.Equal(t, "ggs.command.duration", exporter.metrics[1].Name)
+	require.Equal(t, telemetry.MetricKindHistogram, exporter.metrics[1].Kind)
+	require.Len(t, exporter.metrics[1].Points, 3)
+}
@@ -5,4 +5,5 @@ go 1.16
 require (
 	github.com/stretchr/testify v1.8.0
 	github.com/urfave/cli/v2 v2.11.1
+	gopkg.in/yaml.v3 v3.0.1
 )
@@ -0,0 +1,8 @@
+package telemetry
+
+import "time"
+
+func (p *Provider) ExportSetClock(now func() time.Time) {
+	p.now = now
+	p.start = now()
+}
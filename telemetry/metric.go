@@ -0,0 +1,209 @@
+package telemetry
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Kind of metrics.
+type MetricKind int
+
+const (
+	// Monotonic sum of integers (counter).
+	MetricKindSum MetricKind = iota
+	// Distribution of values in buckets.
+	MetricKindHistogram
+)
+
+// Default bounds of buckets of histograms of latencies in milliseconds.
+var DefaultLatencyBounds = []float64{5, 10, 25, 50, 75, 100, 250, 500, 750, 1000, 2500, 5000, 7500, 10000}
+
+// Cumulative metric from the start of the provider.
+type Metric struct {
+	Name        string
+	Description string
+	Unit        string
+	Kind        MetricKind
+	Start       time.Time
+	Time        time.Time
+	// Bounds of buckets of the histogram.
+	Bounds []float64
+	Points []Point
+}
+
+// Point of a metric per set of attributes.
+type Point struct {
+	Attributes []Attribute
+	// Value of the sum.
+	Value int64
+	// Count, sum and counts per bucket of the histogram (the last one is above all bounds).
+	Count        uint64
+	Sum          float64
+	BucketCounts []uint64
+}
+
+// Instrument which records points of a metric.
+type instrument interface {
+	collect(start, now time.Time) Metric
+}
+
+// Points of an instrument per set of attributes in the order of the first record.
+type points struct {
+	mutex  *sync.Mutex
+	keys   []string
+	points map[string]*Point
+}
+
+func newPoints() points {
+	return points{mutex: &sync.Mutex{}, points: map[string]*Point{}}
+}
+
+// Update the point of the attributes under the lock.
+func (ps *points) update(attrs []Attribute, update func(p *Point)) {
+
+	key := attributesKey(attrs)
+
+	ps.mutex.Lock()
+	defer ps.mutex.Unlock()
+
+	p, ok := ps.points[key]
+	if !ok {
+		p = &Point{Attributes: append([]Attribute(nil), attrs...)}
+		sort.SliceStable(p.Attributes, func(i, j int) bool { return p.Attributes[i].Key < p.Attributes[j].Key })
+		ps.points[key] = p
+		ps.keys = append(ps.keys, key)
+	}
+	update(p)
+}
+
+// Returns copies of the points.
+func (ps *points) snapshot() []Point {
+
+	ps.mutex.Lock()
+	defer ps.mutex.Unlock()
+
+	snapshot := make([]Point, 0, len(ps.keys))
+	for _, key := range ps.keys {
+		p := *ps.points[key]
+		p.BucketCounts = append([]uint64(nil), p.BucketCounts...)
+		snapshot = append(snapshot, p)
+	}
+	return snapshot
+}
+
+// Counter which adds up values.
+type Counter struct {
+	name        string
+	description string
+	unit        string
+	points      points
+}
+
+// Returns the counter of the name, whose unit is like "1" or "{request}".
+// The same counter is returned for the same name.
+func (p *Provider) Counter(name, description, unit string) *Counter {
+
+	if p == nil {
+		return nil
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	for _, i := range p.instruments {
+		if c, ok := i.(*Counter); ok && c.name == name {
+			return c
+		}
+	}
+	c := &Counter{name: name, description: description, unit: unit, points: newPoints()}
+	p.instruments = append(p.instruments, c)
+
+	return c
+}
+
+// Add the value to the point of the attributes.
+func (c *Counter) Add(value int64, attrs ...Attribute) {
+
+	if c == nil {
+		return
+	}
+	c.points.update(attrs, func(p *Point) {
+		p.Value += value
+	})
+}
+
+func (c *Counter) collect(start, now time.Time) Metric {
+	return Metric{
+		Name:        c.name,
+		Description: c.description,
+		Unit:        c.unit,
+		Kind:        MetricKindSum,
+		Start:       start,
+		Time:        now,
+		Points:      c.points.snapshot(),
+	}
+}
+
+// Histogram which counts values in buckets.
+type Histogram struct {
+	name        string
+	description string
+	unit        string
+	bounds      []float64
+	points      points
+}
+
+// Returns the histogram of the name with the bounds of buckets in ascending order.
+// The same histogram is returned for the same name.
+func (p *Provider) Histogram(name, description, unit string, bounds []float64) *Histogram {
+
+	if p == nil {
+		return nil
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	for _, i := range p.instruments {
+		if h, ok := i.(*Histogram); ok && h.name == name {
+			return h
+		}
+	}
+	h := &Histogram{name: name, description: description, unit: unit, bounds: bounds, points: newPoints()}
+	p.instruments = append(p.instruments, h)
+
+	return h
+}
+
+// Record the value in the point of the attributes.
+func (h *Histogram) Record(value float64, attrs ...Attribute) {
+
+	if h == nil {
+		return
+	}
+
+	// Buckets are (-inf, bounds[0]], (bounds[0], bounds[1]], ..., (bounds[n-1], +inf).
+	bucket := sort.SearchFloat64s(h.bounds, value)
+	h.points.update(attrs, func(p *Point) {
+		if p.BucketCounts == nil {
+			p.BucketCounts = make([]uint64, len(h.bounds)+1)
+		}
+		p.Count += 1
+		p.Sum += value
+		p.BucketCounts[bucket] += 1
+	})
+}
+
+func (h *Histogram) collect(start, now time.Time) Metric {
+	return Metric{
+		Name:        h.name,
+		Description: h.description,
+		Unit:        h.unit,
+		Kind:        MetricKindHistogram,
+		Start:       start,
+		Time:        now,
+		Bounds:      h.bounds,
+		Points:      h.points.snapshot(),
+	}
+}
@@ -0,0 +1,179 @@
+package telemetry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kokoichi206/go-git-stats/util"
+)
+
+// Exporter which sends spans and metrics to an OpenTelemetry collector with OTLP/HTTP and JSON.
+// See the specification:
+// https://opentelemetry.io/docs/specs/otlp/#otlphttp
+type otlpExporter struct {
+	endpoint string
+	client   *http.Client
+}
+
+// Returns the exporter which sends spans to <endpoint>/v1/traces and metrics to <endpoint>/v1/metrics
+// (e.g. http://localhost:4318 of a local collector) with the client.
+func NewOTLPExporter(endpoint string, client *http.Client) Exporter {
+
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &otlpExporter{endpoint: strings.TrimSuffix(endpoint, "/"), client: client}
+}
+
+func (e *otlpExporter) ExportSpans(ctx context.Context, resource []Attribute, spans []SpanData) error {
+
+	otlpSpans := make([]map[string]interface{}, 0, len(spans))
+	for _, s := range spans {
+		span := map[string]interface{}{
+			"traceId":           s.TraceID,
+			"spanId":            s.SpanID,
+			"name":              s.Name,
+			"kind":              int(s.Kind),
+			"startTimeUnixNano": unixNano(s.Start),
+			"endTimeUnixNano":   unixNano(s.End),
+			"attributes":        otlpAttributes(s.Attributes),
+			"status":            map[string]interface{}{},
+		}
+		if s.ParentSpanID != "" {
+			span["parentSpanId"] = s.ParentSpanID
+		}
+		if s.Error != "" {
+			// STATUS_CODE_ERROR
+			span["status"] = map[string]interface{}{"code": 2, "message": util.Redact(s.Error)}
+		}
+		otlpSpans = append(otlpSpans, span)
+	}
+
+	return e.post(ctx, "/v1/traces", map[string]interface{}{
+		"resourceSpans": []interface{}{map[string]interface{}{
+			"resource": map[string]interface{}{"attributes": otlpAttributes(resource)},
+			"scopeSpans": []interface{}{map[string]interface{}{
+				"scope": map[string]interface{}{"name": ScopeName},
+				"spans": otlpSpans,
+			}},
+		}},
+	})
+}
+
+func (e *otlpExporter) ExportMetrics(ctx context.Context, resource []Attribute, metrics []Metric) error {
+
+	otlpMetrics := make([]map[string]interface{}, 0, len(metrics))
+	for _, m := range metrics {
+		points := make([]map[string]interface{}, 0, len(m.Points))
+		for _, p := range m.Points {
+			point := map[string]interface{}{
+				"attributes":        otlpAttributes(p.Attributes),
+				"startTimeUnixNano": unixNano(m.Start),
+				"timeUnixNano":      unixNano(m.Time),
+			}
+			if m.Kind == MetricKindHistogram {
+				counts := make([]string, 0, len(p.BucketCounts))
+				for _, c := range p.BucketCounts {
+					counts = append(counts, strconv.FormatUint(c, 10))
+				}
+				point["count"] = strconv.FormatUint(p.Count, 10)
+				point["sum"] = p.Sum
+				point["bucketCounts"] = counts
+				point["explicitBounds"] = m.Bounds
+			} else {
+				point["asInt"] = strconv.FormatInt(p.Value, 10)
+			}
+			points = append(points, point)
+		}
+
+		metric := map[string]interface{}{
+			"name":        m.Name,
+			"description": m.Description,
+			"unit":        m.Unit,
+		}
+		// AGGREGATION_TEMPORALITY_CUMULATIVE
+		if m.Kind == MetricKindHistogram {
+			metric["histogram"] = map[string]interface{}{"aggregationTemporality": 2, "dataPoints": points}
+		} else {
+			metric["sum"] = map[string]interface{}{"aggregationTemporality": 2, "isMonotonic": true, "dataPoints": points}
+		}
+		otlpMetrics = append(otlpMetrics, metric)
+	}
+
+	return e.post(ctx, "/v1/metrics", map[string]interface{}{
+		"resourceMetrics": []interface{}{map[string]interface{}{
+			"resource": map[string]interface{}{"attributes": otlpAttributes(resource)},
+			"scopeMetrics": []interface{}{map[string]interface{}{
+				"scope":   map[string]interface{}{"name": ScopeName},
+				"metrics": otlpMetrics,
+			}},
+		}},
+	})
+}
+
+// Send the request to the path of the endpoint.
+func (e *otlpExporter) post(ctx context.Context, path string, request interface{}) error {
+
+	data, err := json.Marshal(request)
+	if err != nil {
+		return fmt.Errorf("failed to json.Marshal: %w", err)
+	}
+
+	req, err := http.NewRequest("POST", e.endpoint+path, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to http.NewRequest: %w", err)
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to client.Do: %w", err)
+	}
+	defer resp.Body.Close()
+	// Read the body to reuse the connection.
+	io.Copy(ioutil.Discard, resp.Body)
+
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("failed to client.Do: StatusCode is %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// Returns the attributes of OTLP (KeyValue), where strings are redacted.
+func otlpAttributes(attrs []Attribute) []interface{} {
+
+	kvs := make([]interface{}, 0, len(attrs))
+	for _, a := range attrs {
+		var value map[string]interface{}
+		switch v := a.Value.(type) {
+		case string:
+			value = map[string]interface{}{"stringValue": util.Redact(v)}
+		case int64:
+			// 64 bit integers are strings in JSON of OTLP.
+			value = map[string]interface{}{"intValue": strconv.FormatInt(v, 10)}
+		case float64:
+			value = map[string]interface{}{"doubleValue": v}
+		case bool:
+			value = map[string]interface{}{"boolValue": v}
+		default:
+			value = map[string]interface{}{"stringValue": util.Redact(fmt.Sprint(v))}
+		}
+		kvs = append(kvs, map[string]interface{}{"key": a.Key, "value": value})
+	}
+	return kvs
+}
+
+// Returns the time in nanoseconds since the Unix epoch as a string of OTLP.
+func unixNano(t time.Time) string {
+	return strconv.FormatInt(t.UnixNano(), 10)
+}
@@ -0,0 +1,304 @@
+// Package telemetry records spans and metrics of commands and API calls, and exports them
+// to the standard output or an OpenTelemetry collector (OTLP/HTTP JSON).
+// It implements the subset of OpenTelemetry which ggs uses with the standard library only.
+package telemetry
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Name of the instrumentation scope of spans and metrics.
+const ScopeName = "github.com/kokoichi206/go-git-stats"
+
+// Spans are exported in batches of this size, and the rest at Shutdown.
+const batchSize = 512
+
+// Attribute of spans, metrics and the resource.
+// Value is a string, int64, float64 or bool.
+type Attribute struct {
+	Key   string
+	Value interface{}
+}
+
+func String(key, value string) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+func Int(key string, value int) Attribute {
+	return Attribute{Key: key, Value: int64(value)}
+}
+
+func Bool(key string, value bool) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// Kind of spans, whose values are the ones of OTLP.
+type SpanKind int
+
+const (
+	SpanKindInternal SpanKind = 1
+	SpanKindClient   SpanKind = 3
+)
+
+// Span which has ended.
+type SpanData struct {
+	// IDs in hex, ParentSpanID is empty for root spans.
+	TraceID      string
+	SpanID       string
+	ParentSpanID string
+	Name         string
+	Kind         SpanKind
+	Start        time.Time
+	End          time.Time
+	Attributes   []Attribute
+	// Error message of the span, empty if it succeeded.
+	Error string
+}
+
+// Exporter of spans and metrics with the resource (e.g. service.name).
+type Exporter interface {
+	ExportSpans(ctx context.Context, resource []Attribute, spans []SpanData) error
+	ExportMetrics(ctx context.Context, resource []Attribute, metrics []Metric) error
+}
+
+// Provider of spans and metrics, which are exported by the exporter.
+// Methods of a nil provider (and of its spans and instruments) do nothing,
+// so callers need not check whether the telemetry is enabled.
+type Provider struct {
+	mutex    *sync.Mutex
+	exporter Exporter
+	resource []Attribute
+	spans    []SpanData
+	// Root span of the command, which is the parent of spans without the parent in the context.
+	root        *Span
+	instruments []instrument
+	start       time.Time
+	now         func() time.Time
+	// Errors of exports of batches, returned by Shutdown.
+	errs []error
+}
+
+// Returns the provider which exports spans and metrics with the exporter.
+func NewProvider(exporter Exporter, resource ...Attribute) *Provider {
+	return &Provider{
+		mutex:    &sync.Mutex{},
+		exporter: exporter,
+		resource: resource,
+		start:    time.Now(),
+		now:      time.Now,
+	}
+}
+
+// Key of the span in the context.
+type spanKey struct{}
+
+// Returns the context with the span, whose children are started with the context.
+func ContextWithSpan(ctx context.Context, span *Span) context.Context {
+	return context.WithValue(ctx, spanKey{}, span)
+}
+
+// Returns the span of the context, nil if not any.
+func SpanFromContext(ctx context.Context) *Span {
+	span, _ := ctx.Value(spanKey{}).(*Span)
+	return span
+}
+
+// Start the root span of a new trace (e.g. of a command).
+// Spans started without the parent in the context are its children until it ends,
+// since methods of the ApiCaller have no context.
+func (p *Provider) StartRoot(name string, attrs ...Attribute) *Span {
+
+	if p == nil {
+		return nil
+	}
+
+	span := p.newSpan(nil, name, SpanKindInternal, attrs)
+
+	p.mutex.Lock()
+	p.root = span
+	p.mutex.Unlock()
+
+	return span
+}
+
+// Start the span whose parent is the span of the context (or the root span),
+// and returns the context with it.
+func (p *Provider) Start(ctx context.Context, name string, kind SpanKind, attrs ...Attribute) (context.Context, *Span) {
+
+	if p == nil {
+		return ctx, nil
+	}
+
+	parent := SpanFromContext(ctx)
+	if parent == nil {
+		p.mutex.Lock()
+		parent = p.root
+		p.mutex.Unlock()
+	}
+
+	span := p.newSpan(parent, name, kind, attrs)
+	return ContextWithSpan(ctx, span), span
+}
+
+func (p *Provider) newSpan(parent *Span, name string, kind SpanKind, attrs []Attribute) *Span {
+
+	data := SpanData{
+		SpanID:     newID(8),
+		Name:       name,
+		Kind:       kind,
+		Start:      p.now(),
+		Attributes: append([]Attribute(nil), attrs...),
+	}
+	if parent != nil {
+		data.TraceID = parent.data.TraceID
+		data.ParentSpanID = parent.data.SpanID
+	} else {
+		data.TraceID = newID(16)
+	}
+
+	return &Span{provider: p, mutex: &sync.Mutex{}, data: data}
+}
+
+// Returns a random ID of the size in bytes in hex.
+func newID(size int) string {
+
+	id := make([]byte, size)
+	if _, err := rand.Read(id); err != nil {
+		// IDs need not be secure, only unique within the trace.
+		copy(id, fmt.Sprintf("%x", time.Now().UnixNano()))
+	}
+	return hex.EncodeToString(id)
+}
+
+// Span of an operation (e.g. a command or a request).
+type Span struct {
+	provider *Provider
+	mutex    *sync.Mutex
+	data     SpanData
+	ended    bool
+}
+
+// Add the attributes to the span.
+func (s *Span) SetAttributes(attrs ...Attribute) {
+
+	if s == nil {
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.data.Attributes = append(s.data.Attributes, attrs...)
+}
+
+// Mark the span as failed with the error, nil does nothing.
+func (s *Span) RecordError(err error) {
+
+	if s == nil || err == nil {
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.data.Error = err.Error()
+}
+
+// End the span, which is exported later. It does nothing after the first call.
+func (s *Span) End() {
+
+	if s == nil {
+		return
+	}
+
+	s.mutex.Lock()
+	if s.ended {
+		s.mutex.Unlock()
+		return
+	}
+	s.ended = true
+	s.data.End = s.provider.now()
+	data := s.data
+	s.mutex.Unlock()
+
+	s.provider.end(s, data)
+}
+
+func (p *Provider) end(span *Span, data SpanData) {
+
+	p.mutex.Lock()
+	if p.root == span {
+		p.root = nil
+	}
+	p.spans = append(p.spans, data)
+	var batch []SpanData
+	if len(p.spans) >= batchSize {
+		batch, p.spans = p.spans, nil
+	}
+	p.mutex.Unlock()
+
+	if batch != nil {
+		if err := p.exporter.ExportSpans(context.Background(), p.resource, batch); err != nil {
+			p.mutex.Lock()
+			p.errs = append(p.errs, err)
+			p.mutex.Unlock()
+		}
+	}
+}
+
+// Export the spans which have ended and the metrics.
+// It should be called once before the program exits.
+func (p *Provider) Shutdown(ctx context.Context) error {
+
+	if p == nil {
+		return nil
+	}
+
+	p.mutex.Lock()
+	spans := p.spans
+	p.spans = nil
+	errs := p.errs
+	p.errs = nil
+	instruments := p.instruments
+	p.mutex.Unlock()
+
+	if len(spans) > 0 {
+		if err := p.exporter.ExportSpans(ctx, p.resource, spans); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	now := p.now()
+	var metrics []Metric
+	for _, i := range instruments {
+		if m := i.collect(p.start, now); len(m.Points) > 0 {
+			metrics = append(metrics, m)
+		}
+	}
+	if len(metrics) > 0 {
+		if err := p.exporter.ExportMetrics(ctx, p.resource, metrics); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to export the telemetry: %w", errs[0])
+	}
+	return nil
+}
+
+// Returns the key of the set of attributes, which does not depend on their order.
+func attributesKey(attrs []Attribute) string {
+
+	keys := make([]string, 0, len(attrs))
+	for _, a := range attrs {
+		keys = append(keys, fmt.Sprintf("%s=%v", a.Key, a.Value))
+	}
+	sort.Strings(keys)
+	return strings.Join(keys, ",")
+}
@@ -0,0 +1,177 @@
+package telemetry_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kokoichi206/go-git-stats/telemetry"
+	"github.com/stretchr/testify/require"
+)
+
+// Exporter which keeps what is exported.
+type memoryExporter struct {
+	mutex    sync.Mutex
+	resource []telemetry.Attribute
+	spans    []telemetry.SpanData
+	metrics  []telemetry.Metric
+	err      error
+}
+
+func (e *memoryExporter) ExportSpans(ctx context.Context, resource []telemetry.Attribute, spans []telemetry.SpanData) error {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	e.resource = resource
+	e.spans = append(e.spans, spans...)
+	return e.err
+}
+
+func (e *memoryExporter) ExportMetrics(ctx context.Context, resource []telemetry.Attribute, metrics []telemetry.Metric) error {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	e.metrics = append(e.metrics, metrics...)
+	return e.err
+}
+
+// Clock which advances a millisecond per call.
+func newClock() func() time.Time {
+	now := time.Date(2022, 8, 10, 12, 0, 0, 0, time.UTC)
+	return func() time.Time {
+		now = now.Add(time.Millisecond)
+		return now
+	}
+}
+
+func TestProviderSpans(t *testing.T) {
+
+	// Arrange
+	exporter := &memoryExporter{}
+	p := telemetry.NewProvider(exporter, telemetry.String("service.name", "ggs"))
+	p.ExportSetClock(newClock())
+
+	// Act
+	root := p.StartRoot("ggs lines", telemetry.String("ggs.command", "lines"))
+	_, request := p.Start(context.Background(), "GET", telemetry.SpanKindClient)
+	request.SetAttributes(telemetry.Int("http.status_code", 404))
+	request.RecordError(errors.New("not found"))
+	request.End()
+	ctx, child := p.Start(telemetry.ContextWithSpan(context.Background(), root), "child", telemetry.SpanKindInternal)
+	_, grandchild := p.Start(ctx, "grandchild", telemetry.SpanKindInternal)
+	grandchild.End()
+	child.End()
+	root.End()
+	root.End()
+	_, after := p.Start(context.Background(), "after", telemetry.SpanKindInternal)
+	after.End()
+	err := p.Shutdown(context.Background())
+
+	// Assert
+	require.NoError(t, err)
+	require.Equal(t, []telemetry.Attribute{telemetry.String("service.name", "ggs")}, exporter.resource)
+	require.Len(t, exporter.spans, 5)
+	spans := map[string]telemetry.SpanData{}
+	for _, s := range exporter.spans {
+		spans[s.Name] = s
+	}
+
+	require.Empty(t, spans["ggs lines"].ParentSpanID)
+	require.Len(t, spans["ggs lines"].TraceID, 32)
+	require.Len(t, spans["ggs lines"].SpanID, 16)
+	require.Equal(t, []telemetry.Attribute{telemetry.String("ggs.command", "lines")}, spans["ggs lines"].Attributes)
+	require.Equal(t, 7*time.Millisecond, spans["ggs lines"].End.Sub(spans["ggs lines"].Start))
+
+	// Spans without the parent in the context are children of the root span.
+	require.Equal(t, spans["ggs lines"].TraceID, spans["GET"].TraceID)
+	require.Equal(t, spans["ggs lines"].SpanID, spans["GET"].ParentSpanID)
+	require.Equal(t, telemetry.SpanKindClient, spans["GET"].Kind)
+	require.Equal(t, "not found", spans["GET"].Error)
+	require.Equal(t, []telemetry.Attribute{telemetry.Int("http.status_code", 404)}, spans["GET"].Attributes)
+
+	require.Equal(t, spans["ggs lines"].SpanID, spans["child"].ParentSpanID)
+	require.Equal(t, spans["child"].SpanID, spans["grandchild"].ParentSpanID)
+	require.Equal(t, spans["ggs lines"].TraceID, spans["grandchild"].TraceID)
+
+	// The root span has ended.
+	require.Empty(t, spans["after"].ParentSpanID)
+	require.NotEqual(t, spans["ggs lines"].TraceID, spans["after"].TraceID)
+}
+
+func TestProviderMetrics(t *testing.T) {
+
+	// Arrange
+	exporter := &memoryExporter{}
+	p := telemetry.NewProvider(exporter)
+	p.ExportSetClock(newClock())
+	requests := p.Counter("requests", "requests", "{request}")
+	unused := p.Counter("unused", "", "1")
+	latency := p.Histogram("latency", "latency", "ms", []float64{10, 100})
+
+	// Act
+	requests.Add(1, telemetry.String("method", "GET"), telemetry.Int("status", 200))
+	requests.Add(2, telemetry.Int("status", 200), telemetry.String("method", "GET"))
+	p.Counter("requests", "requests", "{request}").Add(1, telemetry.String("method", "GET"), telemetry.Int("status", 404))
+	unused.Add(0)
+	for _, v := range []float64{3, 10, 11, 100, 1000} {
+		latency.Record(v, telemetry.String("method", "GET"))
+	}
+	err := p.Shutdown(context.Background())
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, exporter.metrics, 3)
+
+	m := exporter.metrics[0]
+	require.Equal(t, "requests", m.Name)
+	require.Equal(t, "{request}", m.Unit)
+	require.Equal(t, telemetry.MetricKindSum, m.Kind)
+	require.True(t, m.Start.Before(m.Time))
+	require.Equal(t, []telemetry.Point{
+		{Attributes: []telemetry.Attribute{telemetry.String("method", "GET"), telemetry.Int("status", 200)}, Value: 3},
+		{Attributes: []telemetry.Attribute{telemetry.String("method", "GET"), telemetry.Int("status", 404)}, Value: 1},
+	}, m.Points)
+
+	h := exporter.metrics[2]
+	require.Equal(t, telemetry.MetricKindHistogram, h.Kind)
+	require.Equal(t, []float64{10, 100}, h.Bounds)
+	require.Equal(t, []telemetry.Point{
+		{Attributes: []telemetry.Attribute{telemetry.String("method", "GET")}, Count: 5, Sum: 1124, BucketCounts: []uint64{2, 2, 1}},
+	}, h.Points)
+}
+
+func TestNilProvider(t *testing.T) {
+
+	// Arrange
+	var p *telemetry.Provider
+
+	// Act
+	span := p.StartRoot("ggs")
+	ctx, child := p.Start(context.Background(), "GET", telemetry.SpanKindClient)
+	child.SetAttributes(telemetry.Bool("cache_hit", true))
+	child.RecordError(errors.New("error"))
+	child.End()
+	span.End()
+	p.Counter("requests", "", "1").Add(1)
+	p.Histogram("latency", "", "ms", nil).Record(1)
+	err := p.Shutdown(context.Background())
+
+	// Assert
+	require.NoError(t, err)
+	require.Nil(t, span)
+	require.Nil(t, telemetry.SpanFromContext(ctx))
+}
+
+func TestProviderShutdownError(t *testing.T) {
+
+	// Arrange
+	exporter := &memoryExporter{err: errors.New("collector is down")}
+	p := telemetry.NewProvider(exporter)
+	p.StartRoot("ggs").End()
+
+	// Act
+	err := p.Shutdown(context.Background())
+
+	// Assert
+	require.EqualError(t, err, "failed to export the telemetry: collector is down")
+}
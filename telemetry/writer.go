@@ -0,0 +1,133 @@
+package telemetry
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/kokoichi206/go-git-stats/util"
+)
+
+// Exporter which writes a JSON object per span and per metric (JSON Lines).
+type writerExporter struct {
+	mutex *sync.Mutex
+	w     io.Writer
+}
+
+// Returns the exporter which writes spans and metrics to w (e.g. the standard output) as JSON Lines.
+// Secrets like tokens are redacted.
+func NewWriterExporter(w io.Writer) Exporter {
+	return &writerExporter{mutex: &sync.Mutex{}, w: w}
+}
+
+func (e *writerExporter) ExportSpans(ctx context.Context, resource []Attribute, spans []SpanData) error {
+
+	lines := make([]interface{}, 0, len(spans))
+	for _, s := range spans {
+		lines = append(lines, struct {
+			Type         string                 `json:"type"`
+			Name         string                 `json:"name"`
+			TraceID      string                 `json:"trace_id"`
+			SpanID       string                 `json:"span_id"`
+			ParentSpanID string                 `json:"parent_span_id,omitempty"`
+			Start        string                 `json:"start"`
+			DurationMS   float64                `json:"duration_ms"`
+			Attributes   map[string]interface{} `json:"attributes,omitempty"`
+			Error        string                 `json:"error,omitempty"`
+		}{
+			Type:         "span",
+			Name:         s.Name,
+			TraceID:      s.TraceID,
+			SpanID:       s.SpanID,
+			ParentSpanID: s.ParentSpanID,
+			Start:        s.Start.Format(time.RFC3339Nano),
+			DurationMS:   float64(s.End.Sub(s.Start).Microseconds()) / 1000,
+			Attributes:   attributesMap(s.Attributes),
+			Error:        s.Error,
+		})
+	}
+
+	return e.write(lines)
+}
+
+func (e *writerExporter) ExportMetrics(ctx context.Context, resource []Attribute, metrics []Metric) error {
+
+	type point struct {
+		Attributes   map[string]interface{} `json:"attributes,omitempty"`
+		Value        *int64                 `json:"value,omitempty"`
+		Count        *uint64                `json:"count,omitempty"`
+		Sum          *float64               `json:"sum,omitempty"`
+		BucketCounts []uint64               `json:"bucket_counts,omitempty"`
+	}
+
+	lines := make([]interface{}, 0, len(metrics))
+	for _, m := range metrics {
+		kind := "sum"
+		if m.Kind == MetricKindHistogram {
+			kind = "histogram"
+		}
+
+		points := make([]point, 0, len(m.Points))
+		for i := range m.Points {
+			p := &m.Points[i]
+			if m.Kind == MetricKindHistogram {
+				points = append(points, point{Attributes: attributesMap(p.Attributes), Count: &p.Count, Sum: &p.Sum, BucketCounts: p.BucketCounts})
+			} else {
+				points = append(points, point{Attributes: attributesMap(p.Attributes), Value: &p.Value})
+			}
+		}
+
+		lines = append(lines, struct {
+			Type   string    `json:"type"`
+			Name   string    `json:"name"`
+			Unit   string    `json:"unit,omitempty"`
+			Kind   string    `json:"kind"`
+			Bounds []float64 `json:"bounds,omitempty"`
+			Points []point   `json:"points"`
+		}{
+			Type:   "metric",
+			Name:   m.Name,
+			Unit:   m.Unit,
+			Kind:   kind,
+			Bounds: m.Bounds,
+			Points: points,
+		})
+	}
+
+	return e.write(lines)
+}
+
+func (e *writerExporter) write(lines []interface{}) error {
+
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	for _, line := range lines {
+		data, err := json.Marshal(line)
+		if err != nil {
+			return fmt.Errorf("failed to json.Marshal: %w", err)
+		}
+		if _, err := io.WriteString(e.w, util.Redact(string(data))+"\n"); err != nil {
+			return fmt.Errorf("failed to write the telemetry: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Returns the attributes as a map for JSON.
+func attributesMap(attrs []Attribute) map[string]interface{} {
+
+	if len(attrs) == 0 {
+		return nil
+	}
+
+	m := make(map[string]interface{}, len(attrs))
+	for _, a := range attrs {
+		m[a.Key] = a.Value
+	}
+	return m
+}
@@ -0,0 +1,143 @@
+package util
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httputil"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Header of responses which come from the cache (its value is hit).
+const CacheHeader = "X-Ggs-Cache"
+
+// Settings of the cache of API responses.
+type CacheConfig struct {
+	// Directory of cached responses, empty means CacheDir().
+	Dir string `yaml:"dir"`
+	// How long responses are reused, zero disables the cache.
+	TTL time.Duration `yaml:"ttl"`
+}
+
+// Returns whether responses are cached.
+func (c CacheConfig) Enabled() bool {
+	return c.TTL > 0
+}
+
+// Returns the directory of cached responses.
+// $XDG_CACHE_HOME/ggs if set, otherwise ggs in the user cache directory.
+func CacheDir() (string, error) {
+
+	if dir := os.Getenv("XDG_CACHE_HOME"); dir != "" {
+		return filepath.Join(dir, "ggs"), nil
+	}
+
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to os.UserCacheDir: %w", err)
+	}
+
+	return filepath.Join(dir, "ggs"), nil
+}
+
+// RoundTripper which reuses successful responses of GET requests until they expire.
+// Requests with 'Cache-Control: no-cache' always go to the server.
+// Responses are cached per URL and credentials of the request, so they are not shared between configured tokens.
+// Tokens of the pool share them, since the pool replaces the credentials below the cache.
+type cacheTransport struct {
+	dir  string
+	ttl  time.Duration
+	next http.RoundTripper
+}
+
+func (t *cacheTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+
+	if req.Method != http.MethodGet || req.Header.Get("Cache-Control") == "no-cache" {
+		return t.next.RoundTrip(req)
+	}
+
+	path := filepath.Join(t.dir, cacheKey(req))
+	if res, ok := t.load(path, req); ok {
+		return res, nil
+	}
+
+	res, err := t.next.RoundTrip(req)
+	if err != nil || res.StatusCode != http.StatusOK {
+		return res, err
+	}
+
+	// The body is read and restored.
+	dump, err := httputil.DumpResponse(res, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to httputil.DumpResponse: %w", err)
+	}
+	// Failing to write the cache does not fail the request.
+	_ = t.store(path, dump)
+
+	return res, nil
+}
+
+// Returns the cached response if it has not expired.
+func (t *cacheTransport) load(path string, req *http.Request) (*http.Response, bool) {
+
+	info, err := os.Stat(path)
+	if err != nil || time.Since(info.ModTime()) > t.ttl {
+		return nil, false
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+
+	res, err := http.ReadResponse(bufio.NewReader(bytes.NewReader(data)), req)
+	if err != nil {
+		return nil, false
+	}
+	res.Header.Set(CacheHeader, "hit")
+
+	return res, true
+}
+
+// Write the response to the cache.
+// Responses may contain private data, so only the user can read them.
+func (t *cacheTransport) store(path string, dump []byte) error {
+
+	if err := os.MkdirAll(t.dir, 0700); err != nil {
+		return err
+	}
+
+	f, err := ioutil.TempFile(t.dir, ".response-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := f.Write(dump); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(f.Name(), path)
+}
+
+// Returns the name of the cached response of the request.
+func cacheKey(req *http.Request) string {
+
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%s %s\n", req.Method, req.URL.String())
+	for _, key := range []string{"Authorization", "Accept"} {
+		fmt.Fprintf(hash, "%s: %s\n", key, req.Header.Get(key))
+	}
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
@@ -0,0 +1,112 @@
+package util_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kokoichi206/go-git-stats/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPClientWithCache(t *testing.T) {
+
+	// Arrange
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls += 1
+		if r.URL.Path == "/stats" {
+			// Statistics are being computed.
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+		fmt.Fprintf(w, "%s %d", r.Header.Get("Authorization"), calls)
+	}))
+	defer server.Close()
+
+	dir := filepath.Join(t.TempDir(), "cache")
+	client := util.Config{Cache: util.CacheConfig{Dir: dir, TTL: time.Hour}}.HTTPClient()
+
+	get := func(path, authorization string) (int, string) {
+		t.Helper()
+		req, err := http.NewRequest("GET", server.URL+path, nil)
+		require.NoError(t, err)
+		req.Header.Set("Authorization", authorization)
+		res, err := client.Do(req)
+		require.NoError(t, err)
+		defer res.Body.Close()
+		body, err := ioutil.ReadAll(res.Body)
+		require.NoError(t, err)
+		return res.StatusCode, string(body)
+	}
+
+	// Act & Assert
+	status, body := get("/repos", "token a")
+	require.Equal(t, http.StatusOK, status)
+	require.Equal(t, "token a 1", body)
+
+	// Cached
+	_, body = get("/repos", "token a")
+	require.Equal(t, "token a 1", body)
+	require.Equal(t, 1, calls)
+
+	// Not shared between tokens
+	_, body = get("/repos", "token b")
+	require.Equal(t, "token b 2", body)
+
+	// Only successful responses are cached.
+	status, _ = get("/stats", "token a")
+	require.Equal(t, http.StatusAccepted, status)
+	get("/stats", "token a")
+	require.Equal(t, 4, calls)
+
+	// Only the user can read cached responses.
+	info, err := os.Stat(dir)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0700), info.Mode().Perm())
+
+	// Expired
+	files, err := ioutil.ReadDir(dir)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(files))
+	for _, f := range files {
+		require.Equal(t, os.FileMode(0600), f.Mode().Perm())
+		old := time.Now().Add(-2 * time.Hour)
+		require.NoError(t, os.Chtimes(filepath.Join(dir, f.Name()), old, old))
+	}
+	_, body = get("/repos", "token a")
+	require.Equal(t, "token a 5", body)
+
+	// POST is never cached.
+	res, err := client.Post(server.URL+"/repos", "application/json", nil)
+	require.NoError(t, err)
+	res.Body.Close()
+	require.Equal(t, 6, calls)
+}
+
+func TestHTTPClientWithoutCache(t *testing.T) {
+
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls += 1
+	}))
+	defer server.Close()
+
+	dir := filepath.Join(t.TempDir(), "cache")
+	client := util.Config{Cache: util.CacheConfig{Dir: dir}}.HTTPClient()
+
+	for i := 0; i < 2; i++ {
+		res, err := client.Get(server.URL)
+		require.NoError(t, err)
+		res.Body.Close()
+	}
+
+	require.Equal(t, 2, calls)
+	_, err := os.Stat(dir)
+	require.True(t, os.IsNotExist(err))
+}
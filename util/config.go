@@ -1,43 +1,582 @@
 package util
 
 import (
+	"crypto/x509"
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"regexp"
+	"strconv"
+	"strings"
+	"time"
 )
 
+// Backends which implement api.ApiCaller.
+const (
+	// GitHub REST API (default).
+	BackendGitHub = "github"
+	// Local git repositories.
+	BackendLocal = "local"
+	// GitLab REST API v4.
+	BackendGitLab = "gitlab"
+	// Gitea (and Forgejo) REST API v1.
+	BackendGitea = "gitea"
+	// Bitbucket Cloud REST API 2.0.
+	BackendBitbucket = "bitbucket"
+	// Bitbucket Server (and Data Center) REST API 1.0.
+	BackendBitbucketServer = "bitbucket-server"
+)
+
+// Output formats of the commands.
+const (
+	OutputText = "text"
+	OutputJSON = "json"
+)
+
+// Settings of a git hosting provider other than GitHub.
+type ProviderConfig struct {
+	// Base URL of the provider (e.g. https://gitlab.com).
+	BaseURL string
+	Token   string
+	// User name for basic authentication with the token as the password (optional).
+	Username string
+}
+
+// A GitHub host (or an account) of multi-host reports.
+type HostConfig struct {
+	// Host like GGS_HOST (github.com or GitHub Enterprise Server).
+	Host  string `yaml:"host"`
+	Token string `yaml:"token"`
+	// Where the token comes from, empty if the token is not set.
+	TokenSource string `yaml:"-"`
+}
+
+// GitHub App which authenticates as its installation.
+type AppConfig struct {
+	ID             int64
+	InstallationID int64
+	// Path to the private key (PEM) of the app.
+	PrivateKeyPath string
+}
+
+// Returns whether the GitHub App is configured.
+func (a AppConfig) Configured() bool {
+	return a.ID != 0
+}
+
 // Configurations
 type Config struct {
-	Token      string
+	Token string
+	// Where the token comes from, empty if the token is not set.
+	TokenSource string
+	// GitHub host (github.com or GitHub Enterprise Server).
+	Host       string
 	ApiBaseURL string
+	// Endpoint of GraphQL API of the host (e.g. https://<host>/api/graphql for GitHub Enterprise Server).
+	GraphQLURL string
+	// GitHub App used instead of the token if configured.
+	App AppConfig
+	// GitHub hosts (or accounts) whose results are merged, empty means the single host above.
+	Hosts []HostConfig
+	// Path to the PEM encoded CA bundle (optional).
+	CACert string
+	// Certificates loaded from CACert, nil means the system certificates.
+	RootCAs *x509.CertPool
+	// URL of the proxy of all requests, empty means no proxy.
+	Proxy string
+	// Hosts which are connected without the proxy (like NO_PROXY).
+	NoProxy string
+	// Skip verification of certificates of servers, which is insecure.
+	InsecureSkipVerify bool
+	// Backend to get repositories and statistics from.
+	Backend string
+	// Path to a git repository (or a directory of them) for the local backend.
+	LocalPath string
+	// Mapping of emails and GitHub logins to canonical people.
+	Identities      Identities
+	GitLab          ProviderConfig
+	Gitea           ProviderConfig
+	Bitbucket       ProviderConfig
+	BitbucketServer ProviderConfig
+	// Path to the config file, empty if there is no home directory.
+	ConfigPath string
+	// Name of the profile in the config file, empty if not used.
+	Profile string
+	// Name of the only source of the token other than GGS_TOKEN, empty means all sources.
+	TokenSourceName string
+	// Default user (or organization) when the name flag is not given.
+	User string
+	// Repositories of the reports.
+	Filter RepositoryFilter
+	// Output format of the commands (text or json).
+	Output string
+	// Maximum number of concurrent API calls, zero means unlimited.
+	Concurrency int
+	// Cache of API responses.
+	Cache CacheConfig
+	// Spans and metrics of commands and API calls.
+	Telemetry TelemetryConfig
+	// Tokens of the host which share the requests with the token (token pool).
+	Tokens []string
+	// Problems found while loading the configurations.
+	Problems []Problem
+}
+
+// Where the config file is and which profile is used.
+type ConfigOptions struct {
+	// Path to the config file, empty means [GGS_CONFIG] or config.yml in the config directory.
+	Path string
+	// Name of the profile, empty means [GGS_PROFILE] or profile in the config file.
+	Profile string
 }
 
 // Load configurations for actual usecase.
 func LoadConfig() (Config, error) {
+	return LoadConfigWith(ConfigOptions{})
+}
 
-	token := os.Getenv("GGS_TOKEN")
+// Load configurations from the config file and the profile of the options.
+// Settings are applied in the order of the top level of the config file, the profile,
+// and environment variables. Flags are applied on top of them by the caller.
+func LoadConfigWith(options ConfigOptions) (Config, error) {
 
-	if !isValidFormat(token) {
-		return Config{}, fmt.Errorf("Your token: '%s' is invalid format.\nPlease check your environment variable [GGS_TOKEN].", token)
+	var problems []Problem
+
+	identities, err := loadIdentities()
+	if err != nil {
+		return Config{}, err
+	}
+
+	path, err := configPath(options)
+	if err != nil {
+		return Config{}, err
+	}
+	fileConfig, profile, err := loadFileConfig(path, options)
+	if err != nil {
+		return Config{}, err
+	}
+	file, err := fileConfig.Select(profile)
+	if err != nil {
+		// 'ggs config set' creates the profile.
+		problems = append(problems, Problem{
+			Message: err.Error(),
+			Fix:     fmt.Sprintf("Create the profile with 'ggs --profile %s config set <key> <value>', or select another profile.", profile),
+		})
+		file = fileConfig.Settings
+	}
+
+	config := Config{
+		Backend:         BackendGitHub,
+		Identities:      identities,
+		ConfigPath:      path,
+		Problems:        problems,
+		Profile:         profile,
+		TokenSourceName: getenv("GGS_TOKEN_SOURCE", file.TokenSource),
+		User:            getenv("GGS_USER", file.User),
+		Filter:          RepositoryFilter{Include: file.Include, Exclude: file.Exclude},
+		Output:          getenv("GGS_OUTPUT", file.Output),
+		NoProxy:         firstEnv("NO_PROXY", "no_proxy"),
+		Concurrency:     file.Concurrency,
+		Cache: CacheConfig{
+			Dir: getenv("GGS_CACHE_DIR", file.Cache.Dir),
+			TTL: file.Cache.TTL,
+		},
+		Telemetry: TelemetryConfig{
+			Exporter: getenv("GGS_TELEMETRY", file.Telemetry.Exporter),
+			Endpoint: getenv("GGS_TELEMETRY_ENDPOINT", file.Telemetry.Endpoint),
+		},
+		GitLab: ProviderConfig{
+			BaseURL: getenv("GGS_GITLAB_URL", "https://gitlab.com"),
+			Token:   os.Getenv("GGS_GITLAB_TOKEN"),
+		},
+		Gitea: ProviderConfig{
+			BaseURL: getenv("GGS_GITEA_URL", "https://gitea.com"),
+			Token:   os.Getenv("GGS_GITEA_TOKEN"),
+		},
+		Bitbucket: ProviderConfig{
+			BaseURL:  getenv("GGS_BITBUCKET_URL", "https://api.bitbucket.org"),
+			Token:    os.Getenv("GGS_BITBUCKET_TOKEN"),
+			Username: os.Getenv("GGS_BITBUCKET_USER"),
+		},
+		BitbucketServer: ProviderConfig{
+			// Bitbucket Server is always self-hosted.
+			BaseURL:  os.Getenv("GGS_BITBUCKET_SERVER_URL"),
+			Token:    os.Getenv("GGS_BITBUCKET_SERVER_TOKEN"),
+			Username: os.Getenv("GGS_BITBUCKET_SERVER_USER"),
+		},
 	}
 
-	return Config{
-		Token:      token,
-		ApiBaseURL: "https://api.github.com",
-	}, nil
+	// The proxy of the settings takes precedence over the standard environment variables.
+	config.Proxy = getenv("GGS_PROXY", file.Proxy)
+	if config.Proxy == "" {
+		config.Proxy = firstEnv("HTTPS_PROXY", "https_proxy", "HTTP_PROXY", "http_proxy")
+	}
+	config.loadEnvSettings()
+	if err := config.Validate(); err != nil {
+		config.Problems = append(config.Problems, Problem{
+			Message: err.Error(),
+			Fix:     fmt.Sprintf("Fix the setting with 'ggs config set' (%s) or the environment variable.", path),
+		})
+	}
+
+	// Environment variables take precedence over the config file.
+	host := getenv("GGS_HOST", file.Host)
+	if host == "" {
+		host = DefaultHost
+	}
+	if err := config.SetHost(host); err != nil {
+		return Config{}, err
+	}
+	if config.App, err = loadAppConfig(); err != nil {
+		return Config{}, err
+	}
+	if err := config.ResolveToken(); err != nil {
+		return Config{}, err
+	}
+	config.loadTokens(file.Tokens)
+	if err := config.SetCACert(getenv("GGS_CA_CERT", file.CACert)); err != nil {
+		return Config{}, err
+	}
+
+	// GGS_HOST selects the single host.
+	if os.Getenv("GGS_HOST") == "" {
+		for i, h := range file.Hosts {
+			if h.Host == "" {
+				return Config{}, fmt.Errorf("host of hosts[%d] is empty in the config file", i)
+			}
+			if !isValidFormat(h.Token) {
+				config.Problems = append(config.Problems, Problem{
+					Message: fmt.Sprintf("The token of %s in hosts of the config file is not a GitHub token.", h.Host),
+					Fix:     fmt.Sprintf("Fix the token of %s in %s.", h.Host, config.ConfigPath),
+				})
+				file.Hosts[i].Token = ""
+				continue
+			}
+			if h.Token != "" {
+				file.Hosts[i].TokenSource = TokenSourceConfigFile
+				continue
+			}
+			// GGS_TOKEN is for the single host.
+			token, source, err := lookupToken(h.Host, config.tokenSources())
+			var problemErr interface{ problem() Problem }
+			if errors.As(err, &problemErr) {
+				config.Problems = append(config.Problems, problemErr.problem())
+				token, source, err = "", "", nil
+			}
+			if err != nil {
+				return Config{}, err
+			}
+			file.Hosts[i].Token = token
+			file.Hosts[i].TokenSource = source
+		}
+		config.Hosts = file.Hosts
+	}
+
+	return config, nil
+}
+
+// Load the GitHub App from [GGS_APP_ID], [GGS_APP_INSTALLATION_ID] and [GGS_APP_PRIVATE_KEY].
+func loadAppConfig() (AppConfig, error) {
+
+	if os.Getenv("GGS_APP_ID") == "" {
+		return AppConfig{}, nil
+	}
+
+	var app AppConfig
+	for _, v := range []struct {
+		key   string
+		value *int64
+	}{
+		{"GGS_APP_ID", &app.ID},
+		{"GGS_APP_INSTALLATION_ID", &app.InstallationID},
+	} {
+		id, err := strconv.ParseInt(os.Getenv(v.key), 10, 64)
+		if err != nil || id <= 0 {
+			return AppConfig{}, fmt.Errorf("Your %s: '%s' is invalid.\nPlease check your environment variable [%s].", v.key, os.Getenv(v.key), v.key)
+		}
+		*v.value = id
+	}
+
+	path, err := expandHome(os.Getenv("GGS_APP_PRIVATE_KEY"))
+	if err != nil {
+		return AppConfig{}, err
+	}
+	if path == "" {
+		return AppConfig{}, errors.New("Private key of the GitHub App is not given.\nPlease check your environment variable [GGS_APP_PRIVATE_KEY].")
+	}
+	app.PrivateKeyPath = path
+
+	return app, nil
 }
 
-// Returns whether the string matches the GitHub personal access token format.
+// Set the path to the CA bundle and load its certificates.
+// An empty path means the system certificates.
+func (c *Config) SetCACert(path string) error {
+
+	c.CACert = path
+	c.RootCAs = nil
+	if path == "" {
+		return nil
+	}
+
+	pool, err := LoadCertPool(path)
+	if err != nil {
+		return err
+	}
+	c.RootCAs = pool
+	return nil
+}
+
+// Returns whether repositories of the authenticated user can be listed with the backend.
+func (c Config) Authenticated() bool {
+	switch c.Backend {
+	case BackendLocal:
+		// Local repositories need no access token.
+		return true
+	case BackendGitLab:
+		return c.GitLab.Token != ""
+	case BackendGitea:
+		return c.Gitea.Token != ""
+	case BackendBitbucket:
+		return c.Bitbucket.Token != ""
+	case BackendBitbucketServer:
+		return c.BitbucketServer.Token != ""
+	default:
+		if c.Token != "" || c.App.Configured() {
+			return true
+		}
+		for _, h := range c.Hosts {
+			if h.Token != "" {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Apply the environment variables of the settings which are not strings.
+// Invalid values are not applied, and left as problems.
+func (c *Config) loadEnvSettings() {
+
+	if value := os.Getenv("GGS_CONCURRENCY"); value != "" {
+		concurrency, err := strconv.Atoi(value)
+		if err != nil {
+			c.Problems = append(c.Problems, Problem{
+				Message: fmt.Sprintf("GGS_CONCURRENCY '%s' is not a number.", value),
+				Fix:     "Set the maximum number of concurrent API calls (e.g. 4) to GGS_CONCURRENCY, or unset it.",
+			})
+		} else {
+			c.Concurrency = concurrency
+		}
+	}
+
+	if value := os.Getenv("GGS_INSECURE_SKIP_VERIFY"); value != "" {
+		insecure, err := strconv.ParseBool(value)
+		if err != nil {
+			c.Problems = append(c.Problems, Problem{
+				Message: fmt.Sprintf("GGS_INSECURE_SKIP_VERIFY '%s' is not a boolean.", value),
+				Fix:     "Set true or false to GGS_INSECURE_SKIP_VERIFY, or unset it.",
+			})
+		} else {
+			c.InsecureSkipVerify = insecure
+		}
+	}
+
+	if value := os.Getenv("GGS_CACHE_TTL"); value != "" {
+		ttl, err := time.ParseDuration(value)
+		if err != nil {
+			c.Problems = append(c.Problems, Problem{
+				Message: fmt.Sprintf("GGS_CACHE_TTL '%s' is not a duration.", value),
+				Fix:     "Set a duration like 30m or 1h to GGS_CACHE_TTL, or unset it.",
+			})
+		} else {
+			c.Cache.TTL = ttl
+		}
+	}
+}
+
+// Load the tokens of the token pool from [GGS_TOKENS] (separated by commas) or the config file.
+// Invalid tokens are not used, and left as problems.
+// The first one is used as the token if no token is resolved.
+func (c *Config) loadTokens(tokens []string) {
+
+	source := TokenSourceConfigFile
+	if value := os.Getenv("GGS_TOKENS"); value != "" {
+		tokens = strings.Split(value, ",")
+		source = "GGS_TOKENS"
+	}
+
+	c.Tokens = nil
+	for _, token := range tokens {
+		token = strings.TrimSpace(token)
+		if token == "" {
+			continue
+		}
+		if !isValidFormat(token) {
+			err := &TokenFormatError{Source: source}
+			c.Problems = append(c.Problems, err.problem())
+			continue
+		}
+		c.Tokens = append(c.Tokens, token)
+	}
+
+	if c.Token == "" && len(c.Tokens) > 0 {
+		c.Token = c.Tokens[0]
+		c.TokenSource = source
+	}
+}
+
+// Returns the distinct tokens which share the requests: the token and the tokens of the pool.
+// The GitHub App uses its installation token only.
+func (c Config) TokenPool() []string {
+
+	if c.App.Configured() {
+		return nil
+	}
+
+	var pool []string
+	seen := map[string]bool{}
+	for _, token := range append([]string{c.Token}, c.Tokens...) {
+		if token == "" || seen[token] {
+			continue
+		}
+		seen[token] = true
+		pool = append(pool, token)
+	}
+
+	return pool
+}
+
+// Returns an error if the settings of the config file, environment variables or flags are invalid.
+func (c Config) Validate() error {
+
+	if _, ok := tokenSourceNames[c.TokenSourceName]; c.TokenSourceName != "" && !ok {
+		return fmt.Errorf("token source must be one of %s, but got '%s'", strings.Join(TokenSourceNames(), ", "), c.TokenSourceName)
+	}
+	if c.Output != "" && c.Output != OutputText && c.Output != OutputJSON {
+		return fmt.Errorf("output must be %s or %s, but got '%s'", OutputText, OutputJSON, c.Output)
+	}
+	if c.Concurrency < 0 {
+		return fmt.Errorf("concurrency must not be negative, but got %d", c.Concurrency)
+	}
+	if c.Cache.TTL < 0 {
+		return fmt.Errorf("ttl of the cache must not be negative, but got %s", c.Cache.TTL)
+	}
+	if c.Proxy != "" {
+		if _, err := parseProxy(c.Proxy); err != nil {
+			return err
+		}
+	}
+	if err := c.Telemetry.validate(); err != nil {
+		return err
+	}
+
+	return c.Filter.validate()
+}
+
+// Returns the configurations for the host of multi-host reports.
+// The GitHub App belongs to the single host, so it is not used.
+func (c Config) ForHost(h HostConfig) (Config, error) {
+
+	if err := c.SetHost(h.Host); err != nil {
+		return Config{}, err
+	}
+	c.Token = h.Token
+	c.TokenSource = h.TokenSource
+	c.App = AppConfig{}
+	c.Hosts = nil
+	c.Tokens = nil
+
+	return c, nil
+}
+
+// Returns the environment variable, or the fallback if it is not set.
+func getenv(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+// Load identities from [GGS_IDENTITIES] or identities.yml in the config directory.
+func loadIdentities() (Identities, error) {
+
+	path := os.Getenv("GGS_IDENTITIES")
+	if path == "" {
+		dir, err := ConfigDir()
+		if err != nil {
+			// No home directory means no identities file.
+			return Identities{}, nil
+		}
+		path = filepath.Join(dir, "identities.yml")
+	}
+
+	return LoadIdentities(path)
+}
+
+// Returns the directory of ggs configuration files.
+// $XDG_CONFIG_HOME/ggs if set, otherwise ~/.config/ggs.
+func ConfigDir() (string, error) {
+
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "ggs"), nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to os.UserHomeDir: %w", err)
+	}
+
+	return filepath.Join(home, ".config", "ggs"), nil
+}
+
+// Token types of GitHub.
+const (
+	TokenTypeClassic      = "personal access token (classic)"
+	TokenTypeFineGrained  = "fine-grained personal access token"
+	TokenTypeOAuth        = "OAuth access token"
+	TokenTypeUser         = "GitHub App user access token"
+	TokenTypeInstallation = "GitHub App installation access token"
+	TokenTypeLegacy       = "legacy token"
+)
+
+// Token formats of each token type.
+// Only the prefixes and the characters are checked, since GitHub may change the lengths.
+// Refresh tokens (ghr_) are not accepted since they can not call APIs.
 // see: https://github.blog/2021-04-05-behind-githubs-new-authentication-token-formats/
+// see: https://github.blog/2022-10-18-introducing-fine-grained-personal-access-tokens-for-github/
+var tokenFormats = []struct {
+	tokenType string
+	regex     *regexp.Regexp
+}{
+	{TokenTypeClassic, regexp.MustCompile(`^ghp_[a-zA-Z0-9_]+$`)},
+	{TokenTypeFineGrained, regexp.MustCompile(`^github_pat_[a-zA-Z0-9_]+$`)},
+	{TokenTypeOAuth, regexp.MustCompile(`^gho_[a-zA-Z0-9_]+$`)},
+	{TokenTypeUser, regexp.MustCompile(`^ghu_[a-zA-Z0-9_]+$`)},
+	{TokenTypeInstallation, regexp.MustCompile(`^ghs_[a-zA-Z0-9_]+$`)},
+	// GitHub Enterprise Server before 3.2 (and GitHub.com before 2021) issues tokens of 40 hex characters,
+	// which have no prefix to tell them from other strings.
+	{TokenTypeLegacy, regexp.MustCompile(`^[0-9a-f]{40}$`)},
+}
+
+// Returns the type of the GitHub token by its format, or empty if the format is unknown.
+func TokenType(token string) string {
+	for _, f := range tokenFormats {
+		if f.regex.MatchString(token) {
+			return f.tokenType
+		}
+	}
+	return ""
+}
+
+// Returns whether the string matches one of the GitHub token formats.
 func isValidFormat(token string) bool {
-	// Token format
-	const TokenRegex = `^ghp_[a-zA-Z0-9]{36}$`
 
 	// empty token means that the token has been not set, so it should be true
 	if token == "" {
 		return true
 	}
 
-	r := regexp.MustCompile(TokenRegex)
-	return r.MatchString(token)
+	return TokenType(token) != ""
 }
@@ -0,0 +1,255 @@
+package util
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Credential of a host stored by 'ggs auth login'.
+type Credential struct {
+	Token string `yaml:"token"`
+	// Login of the authenticated user.
+	User string `yaml:"user"`
+}
+
+// Credentials stored in the credentials file, keyed by the host name.
+//
+// Example of the credentials file:
+//
+//	github.com:
+//	  token: gho_xxx
+//	  user: kokoichi206
+type Credentials map[string]Credential
+
+// Returns the path to the credentials file (hosts.yml in the config directory).
+func CredentialsPath() (string, error) {
+
+	dir, err := ConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "hosts.yml"), nil
+}
+
+// Minimum size of key files in bytes.
+const minKeyFileSize = 16
+
+// Load credentials from the file.
+// A file which does not exist means no credentials.
+// A locked file is decrypted with the secret of [GGS_KEY_FILE] or [GGS_PASSPHRASE].
+func LoadCredentials(path string) (Credentials, error) {
+
+	data, locked, err := readCredentialsFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if locked != nil {
+		secret, err := CredentialsSecret()
+		if err != nil {
+			return nil, err
+		}
+		if secret == nil {
+			return nil, &CredentialsLockedError{Path: path}
+		}
+		if data, err = decrypt(secret, *locked); err != nil {
+			return nil, &CredentialsLockedError{Path: path, Err: err}
+		}
+	}
+
+	credentials := Credentials{}
+	if err := yaml.Unmarshal(data, &credentials); err != nil {
+		return nil, fmt.Errorf("failed to yaml.Unmarshal %s: %w", path, err)
+	}
+
+	return credentials, nil
+}
+
+// Save credentials to the file which only the user can read and write.
+// The file stays encrypted with the secret of [GGS_KEY_FILE] or [GGS_PASSPHRASE] if it is locked.
+func SaveCredentials(path string, credentials Credentials) error {
+
+	_, locked, err := readCredentialsFile(path)
+	if err != nil {
+		return err
+	}
+
+	var secret []byte
+	if locked != nil {
+		if secret, err = CredentialsSecret(); err != nil {
+			return err
+		}
+		if secret == nil {
+			return &CredentialsLockedError{Path: path}
+		}
+	}
+
+	return writeCredentialsFile(path, credentials, secret)
+}
+
+// Returns whether the credentials file is encrypted.
+func IsCredentialsLocked(path string) (bool, error) {
+
+	_, locked, err := readCredentialsFile(path)
+	return locked != nil, err
+}
+
+// Encrypt the credentials file with the secret.
+// A file which does not exist is created with no credentials, so that credentials stored later are encrypted.
+func LockCredentials(path string, secret []byte) error {
+
+	data, locked, err := readCredentialsFile(path)
+	if err != nil {
+		return err
+	}
+	if locked != nil {
+		return fmt.Errorf("the credentials file %s is already locked", path)
+	}
+
+	credentials := Credentials{}
+	if err := yaml.Unmarshal(data, &credentials); err != nil {
+		return fmt.Errorf("failed to yaml.Unmarshal %s: %w", path, err)
+	}
+
+	return writeCredentialsFile(path, credentials, secret)
+}
+
+// Decrypt the credentials file with the secret and store it in plaintext.
+func UnlockCredentials(path string, secret []byte) error {
+
+	_, locked, err := readCredentialsFile(path)
+	if err != nil {
+		return err
+	}
+	if locked == nil {
+		return fmt.Errorf("the credentials file %s is not locked", path)
+	}
+
+	data, err := decrypt(secret, *locked)
+	if err != nil {
+		return &CredentialsLockedError{Path: path, Err: err}
+	}
+
+	credentials := Credentials{}
+	if err := yaml.Unmarshal(data, &credentials); err != nil {
+		return fmt.Errorf("failed to yaml.Unmarshal %s: %w", path, err)
+	}
+
+	return writeCredentialsFile(path, credentials, nil)
+}
+
+// Returns the secret to decrypt the credentials file:
+// the content of the key file of [GGS_KEY_FILE] if set, otherwise [GGS_PASSPHRASE].
+// nil is returned if neither is set.
+func CredentialsSecret() ([]byte, error) {
+
+	if path := os.Getenv("GGS_KEY_FILE"); path != "" {
+		path, err := expandHome(path)
+		if err != nil {
+			return nil, err
+		}
+		return ReadKeyFile(path)
+	}
+	if passphrase := os.Getenv("GGS_PASSPHRASE"); passphrase != "" {
+		return []byte(passphrase), nil
+	}
+
+	return nil, nil
+}
+
+// Returns the secret in the key file.
+// Surrounding spaces (e.g. a newline at the end) are ignored.
+func ReadKeyFile(path string) ([]byte, error) {
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to ioutil.ReadFile: %w", err)
+	}
+
+	secret := bytes.TrimSpace(data)
+	if len(secret) < minKeyFileSize {
+		return nil, fmt.Errorf("the key file %s is too short: at least %d bytes are needed", path, minKeyFileSize)
+	}
+
+	return secret, nil
+}
+
+// Read the credentials file.
+// The encrypted data is returned instead if the file is locked.
+func readCredentialsFile(path string) ([]byte, *encrypted, error) {
+
+	data, err := ioutil.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to ioutil.ReadFile: %w", err)
+	}
+
+	// Host names never collide with the keys of the encrypted file.
+	var locked encrypted
+	if err := yaml.Unmarshal(data, &locked); err == nil && locked.Encryption != nil && locked.Data != "" {
+		return nil, &locked, nil
+	}
+
+	return data, nil, nil
+}
+
+// Write the credentials file, which is encrypted with the secret unless it is nil.
+func writeCredentialsFile(path string, credentials Credentials, secret []byte) error {
+
+	data, err := yaml.Marshal(credentials)
+	if err != nil {
+		return fmt.Errorf("failed to yaml.Marshal: %w", err)
+	}
+
+	if secret != nil {
+		locked, err := encrypt(secret, data)
+		if err != nil {
+			return err
+		}
+		body, err := yaml.Marshal(locked)
+		if err != nil {
+			return fmt.Errorf("failed to yaml.Marshal: %w", err)
+		}
+		data = append([]byte("# Encrypted by 'ggs auth lock', run 'ggs auth unlock' to decrypt.\n"), body...)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to os.MkdirAll: %w", err)
+	}
+	if err := ioutil.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to ioutil.WriteFile: %w", err)
+	}
+	// WriteFile does not change the permissions of the existing file.
+	if err := os.Chmod(path, 0600); err != nil {
+		return fmt.Errorf("failed to os.Chmod: %w", err)
+	}
+
+	return nil
+}
+
+// Load the stored credential of the host from the credentials file.
+func loadCredential(host string) (Credential, bool, error) {
+
+	path, err := CredentialsPath()
+	if err != nil {
+		// No home directory means no credentials file.
+		return Credential{}, false, nil
+	}
+
+	credentials, err := LoadCredentials(path)
+	if err != nil {
+		return Credential{}, false, err
+	}
+
+	credential, ok := credentials[HostName(host)]
+	return credential, ok && credential.Token != "", nil
+}
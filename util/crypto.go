@@ -0,0 +1,203 @@
+package util
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"sync"
+)
+
+// Algorithms of encrypted files.
+const (
+	kdfPBKDF2SHA256 = "pbkdf2-sha256"
+	cipherAES256GCM = "aes-256-gcm"
+)
+
+// Iterations of PBKDF2 for new encrypted files (as recommended by OWASP for HMAC-SHA256).
+var pbkdf2Iterations = 600000
+
+// Sizes in bytes.
+const (
+	keySize  = 32
+	saltSize = 16
+)
+
+// Data bound to the ciphertext, so that it is not decrypted as something else.
+var encryptionContext = []byte("ggs encrypted file v1")
+
+// Error of the secret which does not decrypt the data.
+var errWrongSecret = errors.New("the passphrase or the key file is wrong")
+
+// Parameters to decrypt the data.
+type encryption struct {
+	KDF        string `yaml:"kdf"`
+	Iterations int    `yaml:"iterations"`
+	Salt       string `yaml:"salt"`
+	Cipher     string `yaml:"cipher"`
+	Nonce      string `yaml:"nonce"`
+}
+
+// Encrypted data with the parameters.
+type encrypted struct {
+	Encryption *encryption `yaml:"encryption"`
+	Data       string      `yaml:"data"`
+}
+
+// Encrypt the data with the key derived from the secret (a passphrase or the content of a key file).
+func encrypt(secret, plaintext []byte) (encrypted, error) {
+
+	salt := make([]byte, saltSize)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return encrypted{}, fmt.Errorf("failed to rand.Read: %w", err)
+	}
+
+	aead, err := newAEAD(deriveKey(secret, salt, pbkdf2Iterations))
+	if err != nil {
+		return encrypted{}, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return encrypted{}, fmt.Errorf("failed to rand.Read: %w", err)
+	}
+
+	return encrypted{
+		Encryption: &encryption{
+			KDF:        kdfPBKDF2SHA256,
+			Iterations: pbkdf2Iterations,
+			Salt:       base64.StdEncoding.EncodeToString(salt),
+			Cipher:     cipherAES256GCM,
+			Nonce:      base64.StdEncoding.EncodeToString(nonce),
+		},
+		Data: base64.StdEncoding.EncodeToString(aead.Seal(nil, nonce, plaintext, encryptionContext)),
+	}, nil
+}
+
+// Decrypt the data with the key derived from the secret.
+func decrypt(secret []byte, e encrypted) ([]byte, error) {
+
+	params := e.Encryption
+	if params == nil || params.KDF != kdfPBKDF2SHA256 || params.Cipher != cipherAES256GCM || params.Iterations <= 0 {
+		return nil, errors.New("unsupported encryption")
+	}
+
+	salt, err := base64.StdEncoding.DecodeString(params.Salt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode the salt: %w", err)
+	}
+	nonce, err := base64.StdEncoding.DecodeString(params.Nonce)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode the nonce: %w", err)
+	}
+	data, err := base64.StdEncoding.DecodeString(e.Data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode the data: %w", err)
+	}
+
+	aead, err := newAEAD(deriveKey(secret, salt, params.Iterations))
+	if err != nil {
+		return nil, err
+	}
+	if len(nonce) != aead.NonceSize() {
+		return nil, errors.New("invalid size of the nonce")
+	}
+
+	plaintext, err := aead.Open(nil, nonce, data, encryptionContext)
+	if err != nil {
+		// Tampered data can not be told from a wrong secret.
+		return nil, errWrongSecret
+	}
+
+	return plaintext, nil
+}
+
+// Keys derived in this process, since the credentials file is decrypted several times per run
+// and each derivation takes a noticeable time.
+// Keys are looked up by the hash of the secret, the salt and the iterations.
+var derivedKeys = struct {
+	sync.Mutex
+	keys map[[sha256.Size]byte][]byte
+}{keys: map[[sha256.Size]byte][]byte{}}
+
+// Returns the key derived from the secret with PBKDF2-SHA256, which is derived once per process.
+func deriveKey(secret, salt []byte, iterations int) []byte {
+
+	h := sha256.New()
+	var sizes [16]byte
+	binary.BigEndian.PutUint64(sizes[:8], uint64(iterations))
+	binary.BigEndian.PutUint64(sizes[8:], uint64(len(salt)))
+	h.Write(sizes[:])
+	h.Write(salt)
+	h.Write(secret)
+	var id [sha256.Size]byte
+	copy(id[:], h.Sum(nil))
+
+	derivedKeys.Lock()
+	defer derivedKeys.Unlock()
+	if key, ok := derivedKeys.keys[id]; ok {
+		return key
+	}
+	key := pbkdf2(sha256.New, secret, salt, iterations, keySize)
+	derivedKeys.keys[id] = key
+	return key
+}
+
+// Returns AES-GCM with the key.
+func newAEAD(key []byte) (cipher.AEAD, error) {
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aes.NewCipher: %w", err)
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to cipher.NewGCM: %w", err)
+	}
+
+	return aead, nil
+}
+
+// Derive the key from the password with PBKDF2 (RFC 8018).
+// golang.org/x/crypto/pbkdf2 is not a dependency, so it is implemented with the standard library.
+func pbkdf2(h func() hash.Hash, password, salt []byte, iterations, keyLen int) []byte {
+
+	prf := hmac.New(h, password)
+	size := prf.Size()
+	blocks := (keyLen + size - 1) / size
+
+	key := make([]byte, 0, blocks*size)
+	u := make([]byte, size)
+	for block := 1; block <= blocks; block++ {
+		// U1 = PRF(password, salt || INT(block))
+		prf.Reset()
+		prf.Write(salt)
+		var index [4]byte
+		binary.BigEndian.PutUint32(index[:], uint32(block))
+		prf.Write(index[:])
+		u = prf.Sum(u[:0])
+
+		// T = U1 ^ U2 ^ ... ^ Uc
+		t := make([]byte, size)
+		copy(t, u)
+		for i := 1; i < iterations; i++ {
+			prf.Reset()
+			prf.Write(u)
+			u = prf.Sum(u[:0])
+			for j := range t {
+				t[j] ^= u[j]
+			}
+		}
+		key = append(key, t...)
+	}
+
+	return key[:keyLen]
+}
@@ -0,0 +1,77 @@
+package util_test
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"hash"
+	"testing"
+
+	"github.com/kokoichi206/go-git-stats/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPBKDF2(t *testing.T) {
+
+	// Test vectors of RFC 6070 (SHA-1) and RFC 7914 (SHA-256)
+	testCases := []struct {
+		name       string
+		hash       func() hash.Hash
+		password   string
+		salt       string
+		iterations int
+		keyLen     int
+		expected   string
+	}{
+		{
+			name:       "SHA-1 with 1 iteration",
+			hash:       sha1.New,
+			password:   "password",
+			salt:       "salt",
+			iterations: 1,
+			keyLen:     20,
+			expected:   "0c60c80f961f0e71f3a9b524af6012062fe037a6",
+		},
+		{
+			name:       "SHA-1 with 4096 iterations",
+			hash:       sha1.New,
+			password:   "password",
+			salt:       "salt",
+			iterations: 4096,
+			keyLen:     20,
+			expected:   "4b007901b765489abead49d926f721d065a429c1",
+		},
+		{
+			name:       "SHA-1 with multiple blocks",
+			hash:       sha1.New,
+			password:   "passwordPASSWORDpassword",
+			salt:       "saltSALTsaltSALTsaltSALTsaltSALTsalt",
+			iterations: 4096,
+			keyLen:     25,
+			expected:   "3d2eec4fe41c849b80c8d83662c0e44a8b291a964cf2f07038",
+		},
+		{
+			name:       "SHA-256 with multiple blocks",
+			hash:       sha256.New,
+			password:   "passwd",
+			salt:       "salt",
+			iterations: 1,
+			keyLen:     64,
+			expected:   "55ac046e56e3089fec1691c22544b605f94185216dde0465e68b9d57c20dacbc49ca9cccf179b645991664b39d77ef317c71b845b1e30bd509112041d3a19783",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Act
+			key := util.PBKDF2(tc.hash, []byte(tc.password), []byte(tc.salt), tc.iterations, tc.keyLen)
+
+			// Assert
+			require.Equal(t, tc.expected, hex.EncodeToString(key))
+		})
+	}
+}
@@ -1,3 +1,23 @@
 package util
 
 var IsValidFormat = isValidFormat
+
+var PBKDF2 = pbkdf2
+
+// Lower the iterations of PBKDF2 to speed up tests, and returns the function to restore them.
+func ExportSetPBKDF2Iterations(iterations int) func() {
+	old := pbkdf2Iterations
+	pbkdf2Iterations = iterations
+	return func() {
+		pbkdf2Iterations = old
+	}
+}
+
+// Returns the number of keys derived in this process.
+func ExportDerivedKeys() int {
+	derivedKeys.Lock()
+	defer derivedKeys.Unlock()
+	return len(derivedKeys.keys)
+}
+
+var MatchNoProxy = matchNoProxy
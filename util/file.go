@@ -0,0 +1,263 @@
+package util
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Settings in the config file (config.yml in the config directory).
+// Settings at the top level apply to all profiles,
+// and the selected profile overrides them.
+// Environment variables and flags take precedence over both.
+//
+// Example of the config file:
+//
+//	# GitHub Enterprise Server
+//	host: ghe.example.com
+//	# PEM encoded CA bundle of the on-premises instance
+//	ca_cert: ~/.config/ggs/ca.pem
+//	# proxy of all requests (optional, NO_PROXY is respected)
+//	proxy: http://proxy.example.com:8080
+//	# hosts (or accounts) whose results are merged (optional)
+//	hosts:
+//	  - host: github.com
+//	    token: ghp_xxx
+//	  - host: ghe.example.com
+//	    token: ghp_yyy
+//	# profile used when neither --profile nor GGS_PROFILE is given (optional)
+//	profile: work
+//	profiles:
+//	  work:
+//	    host: ghe.example.com
+//	    token_source: gh
+//	    user: my-org
+//	    include: ["my-org/*"]
+//	    exclude: ["*/*-archive"]
+//	    output: json
+//	    concurrency: 4
+//	    cache:
+//	      dir: ~/.cache/ggs
+//	      ttl: 1h
+//	    # spans and metrics of commands and API calls (optional)
+//	    telemetry:
+//	      exporter: otlp
+//	      endpoint: http://localhost:4318
+//	    # tokens which share the requests of large scans (optional)
+//	    tokens:
+//	      - ghp_xxx
+//	      - ghp_yyy
+type FileConfig struct {
+	Settings `yaml:",inline"`
+	// Name of the default profile.
+	Profile  string              `yaml:"profile"`
+	Profiles map[string]Settings `yaml:"profiles"`
+}
+
+// Settings of the config file at the top level or of a profile.
+// Zero values mean not set.
+type Settings struct {
+	Host   string       `yaml:"host"`
+	CACert string       `yaml:"ca_cert"`
+	Hosts  []HostConfig `yaml:"hosts"`
+	// URL of the proxy (HTTPS_PROXY and HTTP_PROXY are used if not set).
+	Proxy string `yaml:"proxy"`
+	// Only this source of the token is looked up (env, credentials, gh or git-credential).
+	TokenSource string `yaml:"token_source"`
+	// Default user (or organization) of the name flag.
+	User string `yaml:"user"`
+	// Patterns of full names of repositories to include and exclude.
+	Include []string `yaml:"include"`
+	Exclude []string `yaml:"exclude"`
+	// Output format (text or json).
+	Output string `yaml:"output"`
+	// Maximum number of concurrent API calls.
+	Concurrency int         `yaml:"concurrency"`
+	Cache       CacheConfig `yaml:"cache"`
+	// Spans and metrics of commands and API calls (optional).
+	Telemetry TelemetryConfig `yaml:"telemetry"`
+	// Tokens of the host which share the requests in addition to the token.
+	Tokens []string `yaml:"tokens"`
+}
+
+// Load settings from the YAML file.
+// A file which does not exist means no settings.
+func LoadFileConfig(path string) (FileConfig, error) {
+
+	data, err := ioutil.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return FileConfig{}, nil
+	}
+	if err != nil {
+		return FileConfig{}, fmt.Errorf("failed to ioutil.ReadFile: %w", err)
+	}
+
+	var file FileConfig
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return FileConfig{}, fmt.Errorf("failed to yaml.Unmarshal %s: %w", path, err)
+	}
+
+	if file.Settings, err = file.Settings.expandHome(); err != nil {
+		return FileConfig{}, err
+	}
+	for name, profile := range file.Profiles {
+		if file.Profiles[name], err = profile.expandHome(); err != nil {
+			return FileConfig{}, err
+		}
+	}
+
+	return file, nil
+}
+
+// Returns the settings of the profile on top of the top-level settings.
+// An empty name means the default profile of the file, if any.
+func (f FileConfig) Select(name string) (Settings, error) {
+
+	if name == "" {
+		name = f.Profile
+	}
+	if name == "" {
+		return f.Settings, nil
+	}
+
+	profile, ok := f.Profiles[name]
+	if !ok {
+		return Settings{}, fmt.Errorf("profile '%s' is not found in the config file", name)
+	}
+
+	return f.Settings.merge(profile), nil
+}
+
+// Returns the settings overridden by the ones set in the other.
+func (s Settings) merge(other Settings) Settings {
+
+	for _, v := range []struct {
+		dst *string
+		src string
+	}{
+		{&s.Host, other.Host},
+		{&s.CACert, other.CACert},
+		{&s.Proxy, other.Proxy},
+		{&s.TokenSource, other.TokenSource},
+		{&s.User, other.User},
+		{&s.Output, other.Output},
+		{&s.Cache.Dir, other.Cache.Dir},
+		{&s.Telemetry.Exporter, other.Telemetry.Exporter},
+		{&s.Telemetry.Endpoint, other.Telemetry.Endpoint},
+	} {
+		if v.src != "" {
+			*v.dst = v.src
+		}
+	}
+	if other.Hosts != nil {
+		s.Hosts = other.Hosts
+	}
+	if other.Include != nil {
+		s.Include = other.Include
+	}
+	if other.Exclude != nil {
+		s.Exclude = other.Exclude
+	}
+	if other.Tokens != nil {
+		s.Tokens = other.Tokens
+	}
+	if other.Concurrency != 0 {
+		s.Concurrency = other.Concurrency
+	}
+	if other.Cache.TTL != 0 {
+		s.Cache.TTL = other.Cache.TTL
+	}
+
+	return s
+}
+
+// Returns the settings whose paths are expanded.
+func (s Settings) expandHome() (Settings, error) {
+
+	var err error
+	if s.CACert, err = expandHome(s.CACert); err != nil {
+		return Settings{}, err
+	}
+	if s.Cache.Dir, err = expandHome(s.Cache.Dir); err != nil {
+		return Settings{}, err
+	}
+
+	return s, nil
+}
+
+// Returns the path to the config file.
+// [GGS_CONFIG] if set, otherwise config.yml in the config directory
+// (empty if there is no home directory).
+func ConfigPath() string {
+
+	if path := os.Getenv("GGS_CONFIG"); path != "" {
+		return path
+	}
+
+	dir, err := ConfigDir()
+	if err != nil {
+		return ""
+	}
+
+	return filepath.Join(dir, "config.yml")
+}
+
+// Returns the path to the config file of the options.
+// The config file given explicitly must exist.
+func configPath(options ConfigOptions) (string, error) {
+
+	path := options.Path
+	if path == "" {
+		path = os.Getenv("GGS_CONFIG")
+	}
+	if path == "" {
+		return ConfigPath(), nil
+	}
+
+	if _, err := os.Stat(path); err != nil {
+		return "", fmt.Errorf("failed to os.Stat: %w", err)
+	}
+	return path, nil
+}
+
+// Load settings from the config file, and returns them with the name of the profile (empty if not used).
+func loadFileConfig(path string, options ConfigOptions) (FileConfig, string, error) {
+
+	var file FileConfig
+	if path != "" {
+		var err error
+		file, err = LoadFileConfig(path)
+		if err != nil {
+			return FileConfig{}, "", err
+		}
+	}
+
+	name := options.Profile
+	if name == "" {
+		name = os.Getenv("GGS_PROFILE")
+	}
+	if name == "" {
+		name = file.Profile
+	}
+
+	return file, name, nil
+}
+
+// Expand a leading ~/ of the path to the home directory.
+func expandHome(path string) (string, error) {
+
+	if len(path) < 2 || path[:2] != "~/" {
+		return path, nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to os.UserHomeDir: %w", err)
+	}
+
+	return filepath.Join(home, path[2:]), nil
+}
@@ -0,0 +1,194 @@
+package util_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kokoichi206/go-git-stats/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadConfigWithProfiles(t *testing.T) {
+
+	home, err := os.UserHomeDir()
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name      string
+		options   util.ConfigOptions
+		env       map[string]string
+		assertion func(t *testing.T, config util.Config, err error)
+	}{
+		{
+			name:    "Default profile of the config file",
+			options: util.ConfigOptions{Path: "testdata/profiles/config.yml"},
+			assertion: func(t *testing.T, config util.Config, err error) {
+				require.NoError(t, err)
+				require.Equal(t, "public", config.Profile)
+				require.Equal(t, "github.com", config.Host)
+				require.Equal(t, "kokoichi206", config.User)
+				require.Equal(t, util.OutputText, config.Output)
+				require.Equal(t, util.RepositoryFilter{Include: []string{"kokoichi206/*"}}, config.Filter)
+				require.False(t, config.Cache.Enabled())
+			},
+		},
+		{
+			name:    "Profile of the option",
+			options: util.ConfigOptions{Path: "testdata/profiles/config.yml", Profile: "work"},
+			env:     map[string]string{"GGS_PROFILE": "public"},
+			assertion: func(t *testing.T, config util.Config, err error) {
+				require.NoError(t, err)
+				require.Equal(t, "work", config.Profile)
+				// Top-level settings which the profile does not override
+				require.Equal(t, "ghe.example.com", config.Host)
+				require.Equal(t, "https://ghe.example.com/api/v3", config.ApiBaseURL)
+				require.Equal(t, "env", config.TokenSourceName)
+				require.Equal(t, "my-org", config.User)
+				require.Equal(t, util.RepositoryFilter{Include: []string{"kokoichi206/*"}, Exclude: []string{"*/*-archive"}}, config.Filter)
+				require.Equal(t, util.OutputJSON, config.Output)
+				require.Equal(t, 4, config.Concurrency)
+				require.Equal(t, util.CacheConfig{Dir: filepath.Join(home, ".cache", "ggs-work"), TTL: time.Hour}, config.Cache)
+			},
+		},
+		{
+			name: "Config file and profile of environment variables",
+			env:  map[string]string{"GGS_CONFIG": "testdata/profiles/config.yml", "GGS_PROFILE": "work"},
+			assertion: func(t *testing.T, config util.Config, err error) {
+				require.NoError(t, err)
+				require.Equal(t, "work", config.Profile)
+				require.Equal(t, "my-org", config.User)
+			},
+		},
+		{
+			name:    "Environment variables take precedence over the profile",
+			options: util.ConfigOptions{Path: "testdata/profiles/config.yml", Profile: "work"},
+			env: map[string]string{
+				"GGS_HOST":        "github.com",
+				"GGS_USER":        "kokoichi206",
+				"GGS_OUTPUT":      "text",
+				"GGS_CONCURRENCY": "8",
+				"GGS_CACHE_DIR":   "/tmp/ggs",
+				"GGS_CACHE_TTL":   "10m",
+			},
+			assertion: func(t *testing.T, config util.Config, err error) {
+				require.NoError(t, err)
+				require.Equal(t, "github.com", config.Host)
+				require.Equal(t, "kokoichi206", config.User)
+				require.Equal(t, util.OutputText, config.Output)
+				require.Equal(t, 8, config.Concurrency)
+				require.Equal(t, util.CacheConfig{Dir: "/tmp/ggs", TTL: 10 * time.Minute}, config.Cache)
+			},
+		},
+		{
+			name:    "Profile not found",
+			options: util.ConfigOptions{Path: "testdata/profiles/config.yml", Profile: "unknown"},
+			assertion: func(t *testing.T, config util.Config, err error) {
+				// 'ggs config set' can create the profile.
+				require.NoError(t, err)
+				require.Equal(t, "unknown", config.Profile)
+				require.Equal(t, "ghe.example.com", config.Host)
+				require.Equal(t, 1, len(config.Problems))
+				require.Equal(t, "profile 'unknown' is not found in the config file", config.Problems[0].Message)
+			},
+		},
+		{
+			name:    "Config file not found",
+			options: util.ConfigOptions{Path: "testdata/profiles/missing.yml"},
+			assertion: func(t *testing.T, config util.Config, err error) {
+				require.Error(t, err)
+			},
+		},
+		{
+			name:    "Invalid pattern",
+			options: util.ConfigOptions{Path: "testdata/profiles/config.yml", Profile: "invalid"},
+			assertion: func(t *testing.T, config util.Config, err error) {
+				require.NoError(t, err)
+				require.Equal(t, 1, len(config.Problems))
+				require.Equal(t, "invalid pattern of repositories: '[kokoichi206'", config.Problems[0].Message)
+			},
+		},
+		{
+			name:    "Invalid output",
+			options: util.ConfigOptions{Path: "testdata/profiles/config.yml"},
+			env:     map[string]string{"GGS_OUTPUT": "xml"},
+			assertion: func(t *testing.T, config util.Config, err error) {
+				require.NoError(t, err)
+				require.Equal(t, 1, len(config.Problems))
+				require.Equal(t, "output must be text or json, but got 'xml'", config.Problems[0].Message)
+			},
+		},
+		{
+			name:    "Invalid concurrency",
+			options: util.ConfigOptions{Path: "testdata/profiles/config.yml"},
+			env:     map[string]string{"GGS_CONCURRENCY": "many"},
+			assertion: func(t *testing.T, config util.Config, err error) {
+				require.NoError(t, err)
+				require.Equal(t, 1, len(config.Problems))
+				require.Equal(t, "GGS_CONCURRENCY 'many' is not a number.", config.Problems[0].Message)
+			},
+		},
+		{
+			name:    "Invalid token source",
+			options: util.ConfigOptions{Path: "testdata/profiles/config.yml"},
+			env:     map[string]string{"GGS_TOKEN_SOURCE": "keyring"},
+			assertion: func(t *testing.T, config util.Config, err error) {
+				require.NoError(t, err)
+				require.Equal(t, 1, len(config.Problems))
+				require.Equal(t, "token source must be one of credentials, env, gh, git-credential, but got 'keyring'", config.Problems[0].Message)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			setenv(t, map[string]string{
+				"XDG_CONFIG_HOME":   t.TempDir(),
+				"GH_CONFIG_DIR":     t.TempDir(),
+				"GIT_CONFIG_GLOBAL": "/dev/null",
+			})
+			for _, key := range []string{"GGS_CONFIG", "GGS_PROFILE", "GGS_HOST", "GGS_TOKEN", "GH_TOKEN", "GITHUB_TOKEN", "GH_ENTERPRISE_TOKEN", "GITHUB_ENTERPRISE_TOKEN",
+				"GGS_USER", "GGS_OUTPUT", "GGS_CONCURRENCY", "GGS_CACHE_DIR", "GGS_CACHE_TTL", "GGS_TOKEN_SOURCE"} {
+				setenv(t, map[string]string{key: tc.env[key]})
+			}
+
+			// Act
+			config, err := util.LoadConfigWith(tc.options)
+
+			// Assert
+			tc.assertion(t, config, err)
+		})
+	}
+}
+
+func TestRepositoryFilter(t *testing.T) {
+
+	filter := util.RepositoryFilter{
+		Include: []string{"kokoichi206/*", "other/go-*"},
+		Exclude: []string{"*/*-archive"},
+	}
+
+	testCases := []struct {
+		fullName string
+		expected bool
+	}{
+		{"kokoichi206/go-git-stats", true},
+		{"Kokoichi206/Utils", true},
+		{"kokoichi206/utils-archive", false},
+		{"other/go-git-stats", true},
+		{"other/utils", false},
+		// Labeled with the host of multi-host reports
+		{"ghe.example.com/kokoichi206/utils", true},
+		{"ghe.example.com/other/utils", false},
+	}
+
+	for _, tc := range testCases {
+		require.Equal(t, tc.expected, filter.Match(tc.fullName), tc.fullName)
+	}
+
+	require.True(t, util.RepositoryFilter{}.Match("kokoichi206/go-git-stats"))
+}
@@ -0,0 +1,59 @@
+package util
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// Patterns of full names of repositories (e.g. kokoichi206/*) in the syntax of path.Match.
+// Patterns are case-insensitive, and full names labeled with the host of multi-host reports
+// are matched without the host as well.
+type RepositoryFilter struct {
+	// Only the matched repositories are included, empty means all repositories.
+	Include []string
+	// The matched repositories are excluded even if included.
+	Exclude []string
+}
+
+// Returns whether the repository passes the filter.
+func (f RepositoryFilter) Match(fullName string) bool {
+
+	if len(f.Include) > 0 && !matchAny(f.Include, fullName) {
+		return false
+	}
+
+	return !matchAny(f.Exclude, fullName)
+}
+
+// Returns an error if some patterns are malformed.
+func (f RepositoryFilter) validate() error {
+
+	for _, pattern := range append(append([]string{}, f.Include...), f.Exclude...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid pattern of repositories: '%s'", pattern)
+		}
+	}
+
+	return nil
+}
+
+// Returns whether some of the patterns match the full name.
+func matchAny(patterns []string, fullName string) bool {
+
+	names := []string{strings.ToLower(fullName)}
+	if parts := strings.Split(names[0], "/"); len(parts) > 2 {
+		// host/owner/repo
+		names = append(names, strings.Join(parts[len(parts)-2:], "/"))
+	}
+
+	for _, pattern := range patterns {
+		for _, name := range names {
+			if ok, _ := path.Match(strings.ToLower(pattern), name); ok {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,100 @@
+package util
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Host of GitHub.com.
+const DefaultHost = "github.com"
+
+// Set the GitHub host and derive the endpoints of REST API and GraphQL API from it.
+//
+//	github.com              -> https://api.github.com, https://api.github.com/graphql
+//	<subdomain>.ghe.com     -> https://api.<subdomain>.ghe.com, https://api.<subdomain>.ghe.com/graphql
+//	ghe.example.com (GHES)  -> https://ghe.example.com/api/v3, https://ghe.example.com/api/graphql
+//
+// The host may have a scheme (https by default) and a port.
+func (c *Config) SetHost(host string) error {
+
+	apiBaseURL, graphQLURL, err := GitHubEndpoints(host)
+	if err != nil {
+		return err
+	}
+
+	c.Host = host
+	c.ApiBaseURL = apiBaseURL
+	c.GraphQLURL = graphQLURL
+	return nil
+}
+
+// Returns the base URL of REST API and the endpoint of GraphQL API of the GitHub host.
+// see: https://docs.github.com/en/enterprise-server@latest/rest/quickstart
+func GitHubEndpoints(host string) (apiBaseURL, graphQLURL string, err error) {
+
+	u, err := parseHost(host)
+	if err != nil {
+		return "", "", err
+	}
+
+	name := strings.ToLower(u.Hostname())
+	switch {
+	case name == DefaultHost || name == "api.github.com":
+		return "https://api.github.com", "https://api.github.com/graphql", nil
+	case strings.HasSuffix(name, ".ghe.com"):
+		// GitHub Enterprise Cloud with data residency.
+		base := fmt.Sprintf("%s://api.%s", u.Scheme, strings.TrimPrefix(u.Host, "api."))
+		return base, base + "/graphql", nil
+	default:
+		// GitHub Enterprise Server.
+		base := fmt.Sprintf("%s://%s", u.Scheme, u.Host)
+		return base + "/api/v3", base + "/api/graphql", nil
+	}
+}
+
+// Returns the URL of the web site of the GitHub host, where users sign in.
+func GitHubWebURL(host string) (string, error) {
+
+	u, err := parseHost(host)
+	if err != nil {
+		return "", err
+	}
+
+	name := strings.ToLower(u.Hostname())
+	switch {
+	case name == DefaultHost || name == "api.github.com":
+		return "https://github.com", nil
+	default:
+		return fmt.Sprintf("%s://%s", u.Scheme, strings.TrimPrefix(u.Host, "api.")), nil
+	}
+}
+
+// Parse the host which may have a scheme (https by default) and a port.
+func parseHost(host string) (*url.URL, error) {
+
+	raw := strings.TrimSuffix(strings.TrimSpace(host), "/")
+	if !strings.Contains(raw, "://") {
+		raw = "https://" + raw
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to url.Parse: %w", err)
+	}
+	if u.Host == "" || (u.Scheme != "https" && u.Scheme != "http") || strings.Trim(u.Path, "/") != "" {
+		return nil, fmt.Errorf("invalid GitHub host: '%s'", host)
+	}
+
+	return u, nil
+}
+
+// Returns the host name (and the port) without the scheme, which labels repositories of the host.
+func HostName(host string) string {
+
+	name := strings.TrimSuffix(strings.TrimSpace(host), "/")
+	if i := strings.Index(name, "://"); i >= 0 {
+		name = name[i+3:]
+	}
+
+	return strings.ToLower(name)
+}
@@ -0,0 +1,119 @@
+package util_test
+
+import (
+	"testing"
+
+	"github.com/kokoichi206/go-git-stats/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGitHubEndpoints(t *testing.T) {
+
+	testCases := []struct {
+		name       string
+		host       string
+		apiBaseURL string
+		graphQLURL string
+		err        string
+	}{
+		{
+			name:       "GitHub.com",
+			host:       "github.com",
+			apiBaseURL: "https://api.github.com",
+			graphQLURL: "https://api.github.com/graphql",
+		},
+		{
+			name:       "GitHub.com API host",
+			host:       "https://api.github.com/",
+			apiBaseURL: "https://api.github.com",
+			graphQLURL: "https://api.github.com/graphql",
+		},
+		{
+			name:       "GitHub Enterprise Server",
+			host:       "ghe.example.com",
+			apiBaseURL: "https://ghe.example.com/api/v3",
+			graphQLURL: "https://ghe.example.com/api/graphql",
+		},
+		{
+			name:       "GitHub Enterprise Server with scheme and port",
+			host:       "http://ghe.example.com:8080/",
+			apiBaseURL: "http://ghe.example.com:8080/api/v3",
+			graphQLURL: "http://ghe.example.com:8080/api/graphql",
+		},
+		{
+			name:       "GitHub Enterprise Cloud with data residency",
+			host:       "octocorp.ghe.com",
+			apiBaseURL: "https://api.octocorp.ghe.com",
+			graphQLURL: "https://api.octocorp.ghe.com/graphql",
+		},
+		{
+			name: "NG path",
+			host: "ghe.example.com/api/v3",
+			err:  "invalid GitHub host: 'ghe.example.com/api/v3'",
+		},
+		{
+			name: "NG scheme",
+			host: "ftp://ghe.example.com",
+			err:  "invalid GitHub host: 'ftp://ghe.example.com'",
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			apiBaseURL, graphQLURL, err := util.GitHubEndpoints(tc.host)
+
+			// Assert
+			if tc.err != "" {
+				require.Error(t, err)
+				require.Equal(t, tc.err, err.Error())
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.apiBaseURL, apiBaseURL)
+			require.Equal(t, tc.graphQLURL, graphQLURL)
+		})
+	}
+}
+
+func TestSetHost(t *testing.T) {
+
+	config := util.Config{}
+
+	// Act
+	err := config.SetHost("ghe.example.com")
+
+	// Assert
+	require.NoError(t, err)
+	require.Equal(t, "ghe.example.com", config.Host)
+	require.Equal(t, "https://ghe.example.com/api/v3", config.ApiBaseURL)
+	require.Equal(t, "https://ghe.example.com/api/graphql", config.GraphQLURL)
+
+	// Invalid host does not change the config.
+	require.Error(t, config.SetHost("ghe.example.com/path"))
+	require.Equal(t, "ghe.example.com", config.Host)
+}
+
+func TestHostName(t *testing.T) {
+	require.Equal(t, "github.com", util.HostName("github.com"))
+	require.Equal(t, "ghe.example.com:8443", util.HostName("https://GHE.example.com:8443/"))
+}
+
+func TestGitHubWebURL(t *testing.T) {
+
+	for host, expected := range map[string]string{
+		"github.com":                "https://github.com",
+		"https://api.github.com":    "https://github.com",
+		"octocorp.ghe.com":          "https://octocorp.ghe.com",
+		"http://ghe.example.com:80": "http://ghe.example.com:80",
+	} {
+		webURL, err := util.GitHubWebURL(host)
+		require.NoError(t, err)
+		require.Equal(t, expected, webURL)
+	}
+
+	_, err := util.GitHubWebURL("ghe.example.com/path")
+	require.Error(t, err)
+}
@@ -0,0 +1,100 @@
+package util
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sync"
+)
+
+// Load the PEM encoded CA bundle in addition to the system certificates.
+func LoadCertPool(path string) (*x509.CertPool, error) {
+
+	pem, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to ioutil.ReadFile: %w", err)
+	}
+
+	pool, err := x509.SystemCertPool()
+	if err != nil || pool == nil {
+		// The system pool is not available on some platforms.
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no certificates found in the CA bundle: %s", path)
+	}
+
+	return pool, nil
+}
+
+// Returns the HTTP client for the configurations.
+// Requests go through the proxy if configured, servers are verified with the custom CA bundle if configured
+// (or not verified at all with InsecureSkipVerify), and responses are cached if the cache is enabled.
+func (c Config) HTTPClient() *http.Client {
+	return c.HTTPClientWith(nil)
+}
+
+// Returns the HTTP client like HTTPClient, whose transport to the server is wrapped by the function (if not nil).
+// The wrapper is under the cache, so cached responses do not reach it.
+func (c Config) HTTPClientWith(wrap func(http.RoundTripper) http.RoundTripper) *http.Client {
+
+	transport := c.sharedTransport()
+	if wrap != nil {
+		transport = wrap(transport)
+	}
+
+	if c.Cache.Enabled() {
+		dir := c.Cache.Dir
+		if dir == "" {
+			dir, _ = CacheDir()
+		}
+		// No cache directory means no cache.
+		if dir != "" {
+			transport = &cacheTransport{dir: dir, ttl: c.Cache.TTL, next: transport}
+		}
+	}
+
+	return &http.Client{Transport: transport}
+}
+
+// Idle connections kept per host, which are reused by concurrent API calls (2 by default).
+const maxIdleConnsPerHost = 32
+
+// Settings of connections, which are shared by the same settings.
+type transportKey struct {
+	rootCAs            *x509.CertPool
+	proxy              string
+	noProxy            string
+	insecureSkipVerify bool
+}
+
+var (
+	transportsMutex sync.Mutex
+	transports      = map[transportKey]*http.Transport{}
+)
+
+// Returns the transport of the proxy and the TLS settings, which is shared to reuse connections.
+// HTTPS_PROXY and NO_PROXY are applied by the configurations, not by the transport.
+func (c Config) sharedTransport() http.RoundTripper {
+
+	key := transportKey{rootCAs: c.RootCAs, proxy: c.Proxy, noProxy: c.NoProxy, insecureSkipVerify: c.InsecureSkipVerify}
+
+	transportsMutex.Lock()
+	defer transportsMutex.Unlock()
+
+	if t, ok := transports[key]; ok {
+		return t
+	}
+
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.MaxIdleConnsPerHost = maxIdleConnsPerHost
+	t.Proxy = c.proxyFunc()
+	if c.RootCAs != nil || c.InsecureSkipVerify {
+		t.TLSClientConfig = &tls.Config{RootCAs: c.RootCAs, InsecureSkipVerify: c.InsecureSkipVerify}
+	}
+	transports[key] = t
+
+	return t
+}
@@ -0,0 +1,86 @@
+package util_test
+
+import (
+	"encoding/pem"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/kokoichi206/go-git-stats/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPClientWithCACert(t *testing.T) {
+
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	defer ts.Close()
+
+	// CA bundle of the self-signed certificate of the test server.
+	path := filepath.Join(t.TempDir(), "ca.pem")
+	certificate := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ts.Certificate().Raw})
+	require.NoError(t, ioutil.WriteFile(path, certificate, 0600))
+
+	// Without the CA bundle
+	_, err := util.Config{}.HTTPClient().Get(ts.URL)
+	require.Error(t, err)
+
+	// With the CA bundle
+	config := util.Config{}
+	require.NoError(t, config.SetCACert(path))
+	require.Equal(t, path, config.CACert)
+
+	resp, err := config.HTTPClient().Get(ts.URL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestSetCACertError(t *testing.T) {
+
+	dir := t.TempDir()
+	invalid := filepath.Join(dir, "invalid.pem")
+	require.NoError(t, ioutil.WriteFile(invalid, []byte("not a certificate"), 0600))
+
+	config := util.Config{}
+
+	require.Error(t, config.SetCACert(filepath.Join(dir, "missing.pem")))
+	err := config.SetCACert(invalid)
+	require.Error(t, err)
+	require.Equal(t, "no certificates found in the CA bundle: "+invalid, err.Error())
+	require.Nil(t, config.RootCAs)
+}
+
+func TestHTTPClientReusesConnections(t *testing.T) {
+
+	// Arrange
+	var connections int32
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	ts.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			atomic.AddInt32(&connections, 1)
+		}
+	}
+	ts.Start()
+	defer ts.Close()
+
+	// Act
+	for i := 0; i < 5; i++ {
+		// A client per call like api.New
+		resp, err := util.Config{}.HTTPClient().Get(ts.URL)
+		require.NoError(t, err)
+		_, err = ioutil.ReadAll(resp.Body)
+		require.NoError(t, err)
+		resp.Body.Close()
+	}
+
+	// Assert
+	require.Equal(t, int32(1), atomic.LoadInt32(&connections))
+}
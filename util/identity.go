@@ -0,0 +1,125 @@
+package util
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// GitHub no-reply email: '<id>+<login>@users.noreply.github.com' or '<login>@users.noreply.github.com'.
+// see: https://docs.github.com/en/account-and-profile/setting-up-and-managing-your-personal-account-on-github/managing-email-preferences/setting-your-commit-email-address
+var noReplyEmailRegex = regexp.MustCompile(`^(?:\d+\+)?([^@]+)@users\.noreply\.github\.com$`)
+
+// A canonical person who has several identities.
+type Person struct {
+	Name   string   `yaml:"name"`
+	Emails []string `yaml:"emails"`
+	// GitHub logins.
+	Logins []string `yaml:"logins"`
+}
+
+// Mapping of emails and GitHub logins to canonical people.
+//
+// Example of the identities file:
+//
+//	# mailmap file applied to all local repositories in addition to their .mailmap (optional)
+//	mailmap: ~/.mailmap
+//	people:
+//	  - name: kokoichi206
+//	    emails:
+//	      - kokoichi206@example.com
+//	      - kokoichi@work.example.com
+//	    logins:
+//	      - kokoichi206
+type Identities struct {
+	Mailmap string   `yaml:"mailmap"`
+	People  []Person `yaml:"people"`
+}
+
+// Load identities from the YAML file.
+// A file which does not exist means no identities.
+func LoadIdentities(path string) (Identities, error) {
+
+	data, err := ioutil.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return Identities{}, nil
+	}
+	if err != nil {
+		return Identities{}, fmt.Errorf("failed to ioutil.ReadFile: %w", err)
+	}
+
+	var identities Identities
+	if err := yaml.Unmarshal(data, &identities); err != nil {
+		return Identities{}, fmt.Errorf("failed to yaml.Unmarshal %s: %w", path, err)
+	}
+
+	identities.Mailmap, err = expandHome(identities.Mailmap)
+	if err != nil {
+		return Identities{}, err
+	}
+
+	for i, p := range identities.People {
+		if p.Name == "" {
+			return Identities{}, fmt.Errorf("name of people[%d] is empty in %s", i, path)
+		}
+	}
+
+	return identities, nil
+}
+
+// Find the person by the name, an email or a GitHub login (case insensitive).
+func (ids Identities) Find(key string) (Person, bool) {
+
+	for _, p := range ids.People {
+		if strings.EqualFold(p.Name, key) || containsFold(p.Emails, key) || containsFold(p.Logins, key) {
+			return p, true
+		}
+	}
+
+	return Person{}, false
+}
+
+// Returns the canonical author of the commit email.
+// It is the name of the person if the email (or the GitHub login of a no-reply email) is known,
+// otherwise the email itself.
+func (ids Identities) Author(email string) string {
+
+	for _, p := range ids.People {
+		if containsFold(p.Emails, email) {
+			return p.Name
+		}
+	}
+
+	if m := noReplyEmailRegex.FindStringSubmatch(strings.ToLower(email)); m != nil {
+		for _, p := range ids.People {
+			if containsFold(p.Logins, m[1]) {
+				return p.Name
+			}
+		}
+	}
+
+	return strings.ToLower(email)
+}
+
+// Returns the canonical author of the name, email or GitHub login given by users.
+func (ids Identities) Resolve(key string) string {
+	if p, ok := ids.Find(key); ok {
+		return p.Name
+	}
+	return ids.Author(key)
+}
+
+// Returns whether the slice contains the string (case insensitive).
+func containsFold(values []string, s string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
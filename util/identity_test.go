@@ -0,0 +1,144 @@
+package util_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/kokoichi206/go-git-stats/util"
+	"github.com/stretchr/testify/require"
+)
+
+const identitiesYAML = `
+mailmap: /etc/ggs/mailmap
+people:
+  - name: kokoichi206
+    emails:
+      - kokoichi206@example.com
+      - Kokoichi@work.example.com
+    logins:
+      - kokoichi206
+  - name: someone
+    emails:
+      - someone@example.com
+`
+
+func TestLoadIdentities(t *testing.T) {
+
+	dir := t.TempDir()
+
+	testCases := []struct {
+		name      string
+		content   string
+		assertion func(t *testing.T, identities util.Identities, err error)
+	}{
+		{
+			name:    "OK",
+			content: identitiesYAML,
+			assertion: func(t *testing.T, identities util.Identities, err error) {
+				require.NoError(t, err)
+				require.Equal(t, "/etc/ggs/mailmap", identities.Mailmap)
+				require.Equal(t, 2, len(identities.People))
+				require.Equal(t, "kokoichi206", identities.People[0].Name)
+				require.Equal(t, []string{"kokoichi206@example.com", "Kokoichi@work.example.com"}, identities.People[0].Emails)
+				require.Equal(t, []string{"kokoichi206"}, identities.People[0].Logins)
+			},
+		},
+		{
+			name:    "Invalid YAML",
+			content: "people: [",
+			assertion: func(t *testing.T, identities util.Identities, err error) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "yaml.Unmarshal")
+			},
+		},
+		{
+			name:    "Person without name",
+			content: "people:\n  - emails: [a@example.com]\n",
+			assertion: func(t *testing.T, identities util.Identities, err error) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "name of people[0] is empty")
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			path := filepath.Join(dir, "identities.yml")
+			require.NoError(t, ioutil.WriteFile(path, []byte(tc.content), 0o600))
+
+			// Act
+			identities, err := util.LoadIdentities(path)
+
+			// Assert
+			tc.assertion(t, identities, err)
+		})
+	}
+
+	t.Run("OK file does not exist", func(t *testing.T) {
+		identities, err := util.LoadIdentities(filepath.Join(dir, "not-found.yml"))
+		require.NoError(t, err)
+		require.Empty(t, identities.People)
+	})
+}
+
+func TestIdentities(t *testing.T) {
+
+	path := filepath.Join(t.TempDir(), "identities.yml")
+	require.NoError(t, ioutil.WriteFile(path, []byte(identitiesYAML), 0o600))
+	identities, err := util.LoadIdentities(path)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name     string
+		act      func() string
+		expected string
+	}{
+		{
+			name:     "Author of known email",
+			act:      func() string { return identities.Author("kokoichi@WORK.example.com") },
+			expected: "kokoichi206",
+		},
+		{
+			name:     "Author of no-reply email",
+			act:      func() string { return identities.Author("52474650+kokoichi206@users.noreply.github.com") },
+			expected: "kokoichi206",
+		},
+		{
+			name:     "Author of old no-reply email",
+			act:      func() string { return identities.Author("kokoichi206@users.noreply.github.com") },
+			expected: "kokoichi206",
+		},
+		{
+			name:     "Author of unknown email",
+			act:      func() string { return identities.Author("Unknown@example.com") },
+			expected: "unknown@example.com",
+		},
+		{
+			name:     "Resolve name",
+			act:      func() string { return identities.Resolve("Someone") },
+			expected: "someone",
+		},
+		{
+			name:     "Resolve GitHub login",
+			act:      func() string { return identities.Resolve("kokoichi206") },
+			expected: "kokoichi206",
+		},
+		{
+			name:     "Resolve unknown email",
+			act:      func() string { return identities.Resolve("unknown@example.com") },
+			expected: "unknown@example.com",
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, tc.act())
+		})
+	}
+}
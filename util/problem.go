@@ -0,0 +1,58 @@
+package util
+
+import "fmt"
+
+// Problem of the configurations found while loading them.
+// Commands stop with the problems, but 'ggs doctor' shows them with the fixes.
+type Problem struct {
+	// What is wrong.
+	Message string
+	// How to fix it.
+	Fix string
+}
+
+// Error of the token which is not a GitHub token.
+type TokenFormatError struct {
+	// Where the token comes from (e.g. GGS_TOKEN).
+	Source string
+}
+
+func (e *TokenFormatError) Error() string {
+	return fmt.Sprintf("the token of %s is invalid format", e.Source)
+}
+
+// Problem of the token with the fix.
+func (e *TokenFormatError) problem() Problem {
+	return Problem{
+		Message: fmt.Sprintf("The token of %s is not a GitHub token.", e.Source),
+		Fix:     fmt.Sprintf("Set a token which starts with ghp_, github_pat_, gho_, ghu_ or ghs_ to %s, or unset it.", e.Source),
+	}
+}
+
+// Error of the credentials file which can not be decrypted.
+type CredentialsLockedError struct {
+	// Path to the credentials file.
+	Path string
+	// Why the file can not be decrypted, nil means no secret is given.
+	Err error
+}
+
+func (e *CredentialsLockedError) Error() string {
+	if e.Err == nil {
+		return fmt.Sprintf("the credentials file %s is locked: set [GGS_PASSPHRASE] or [GGS_KEY_FILE]", e.Path)
+	}
+	return fmt.Sprintf("failed to decrypt the credentials file %s: %v", e.Path, e.Err)
+}
+
+func (e *CredentialsLockedError) Unwrap() error {
+	return e.Err
+}
+
+// Problem of the credentials file with the fix.
+// A locked file without the secret is not a problem, since its tokens are skipped.
+func (e *CredentialsLockedError) problem() Problem {
+	return Problem{
+		Message: fmt.Sprintf("The credentials file %s can not be decrypted: %v.", e.Path, e.Err),
+		Fix:     "Set the passphrase (GGS_PASSPHRASE) or the key file (GGS_KEY_FILE) used by 'ggs auth lock'.",
+	}
+}
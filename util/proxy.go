@@ -0,0 +1,122 @@
+package util
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// Returns the first environment variable which is set.
+func firstEnv(keys ...string) string {
+	for _, key := range keys {
+		if value := os.Getenv(key); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// Returns the URL of the proxy.
+// A proxy without the scheme (e.g. proxy.example.com:8080) is an HTTP proxy.
+func parseProxy(proxy string) (*url.URL, error) {
+
+	if !strings.Contains(proxy, "://") {
+		proxy = "http://" + proxy
+	}
+
+	u, err := url.Parse(proxy)
+	if err != nil {
+		return nil, fmt.Errorf("proxy must be a URL like http://proxy.example.com:8080, but got '%s'", proxy)
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5":
+	default:
+		return nil, fmt.Errorf("scheme of the proxy must be http, https or socks5, but got '%s'", u.Scheme)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("proxy must be a URL like http://proxy.example.com:8080, but got '%s'", proxy)
+	}
+
+	return u, nil
+}
+
+// Returns the function which selects the proxy of the request, nil means no proxy.
+// Hosts which match NoProxy are connected directly.
+func (c Config) proxyFunc() func(*http.Request) (*url.URL, error) {
+
+	if c.Proxy == "" {
+		return nil
+	}
+
+	// Validated while loading the configurations.
+	proxy, err := parseProxy(c.Proxy)
+	if err != nil {
+		return func(*http.Request) (*url.URL, error) {
+			return nil, err
+		}
+	}
+
+	return func(req *http.Request) (*url.URL, error) {
+		if matchNoProxy(c.NoProxy, req.URL.Host) {
+			return nil, nil
+		}
+		return proxy, nil
+	}
+}
+
+// Returns whether the host (with the port if any) matches NO_PROXY.
+// NO_PROXY is a list separated by commas of:
+// '*' (all hosts), IP addresses, CIDR ranges and domains (with their subdomains),
+// which may have ports (e.g. ghe.example.com:8443).
+func matchNoProxy(noProxy, hostport string) bool {
+
+	host, port, err := net.SplitHostPort(hostport)
+	if err != nil {
+		host, port = hostport, ""
+	}
+	host = strings.ToLower(host)
+	ip := net.ParseIP(host)
+
+	for _, entry := range strings.Split(noProxy, ",") {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		if entry == "" {
+			continue
+		}
+		if entry == "*" {
+			return true
+		}
+
+		if _, cidr, err := net.ParseCIDR(entry); err == nil {
+			if ip != nil && cidr.Contains(ip) {
+				return true
+			}
+			continue
+		}
+
+		entryHost, entryPort, err := net.SplitHostPort(entry)
+		if err != nil {
+			entryHost, entryPort = entry, ""
+		}
+		if entryPort != "" && entryPort != port {
+			continue
+		}
+
+		if entryIP := net.ParseIP(entryHost); entryIP != nil {
+			if ip != nil && entryIP.Equal(ip) {
+				return true
+			}
+			continue
+		}
+
+		// .example.com and example.com match example.com and its subdomains.
+		domain := strings.TrimPrefix(entryHost, ".")
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+
+	return false
+}
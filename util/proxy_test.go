@@ -0,0 +1,154 @@
+package util_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/kokoichi206/go-git-stats/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatchNoProxy(t *testing.T) {
+
+	testCases := []struct {
+		name     string
+		noProxy  string
+		host     string
+		expected bool
+	}{
+		{name: "Empty", noProxy: "", host: "api.github.com", expected: false},
+		{name: "All hosts", noProxy: "*", host: "api.github.com", expected: true},
+		{name: "Domain", noProxy: "github.com", host: "github.com", expected: true},
+		{name: "Subdomain", noProxy: "github.com", host: "api.github.com:443", expected: true},
+		{name: "Subdomain with the dot", noProxy: ".github.com", host: "api.github.com", expected: true},
+		{name: "Other domain", noProxy: "github.com", host: "notgithub.com", expected: false},
+		{name: "One of the list", noProxy: "localhost, .internal,GHE.example.com", host: "ghe.example.com", expected: true},
+		{name: "Port", noProxy: "ghe.example.com:8443", host: "ghe.example.com:8443", expected: true},
+		{name: "Other port", noProxy: "ghe.example.com:8443", host: "ghe.example.com:443", expected: false},
+		{name: "IP address", noProxy: "10.0.0.1", host: "10.0.0.1:443", expected: true},
+		{name: "CIDR", noProxy: "10.0.0.0/8", host: "10.1.2.3", expected: true},
+		{name: "Out of CIDR", noProxy: "10.0.0.0/8", host: "192.168.0.1", expected: false},
+		{name: "IPv6", noProxy: "::1", host: "[::1]:8080", expected: true},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Act
+			result := util.MatchNoProxy(tc.noProxy, tc.host)
+
+			// Assert
+			require.Equal(t, tc.expected, result)
+		})
+	}
+}
+
+func TestLoadConfigWithProxy(t *testing.T) {
+
+	testCases := []struct {
+		name      string
+		env       map[string]string
+		file      string
+		assertion func(t *testing.T, config util.Config)
+	}{
+		{
+			name: "OK with HTTPS_PROXY and NO_PROXY",
+			env:  map[string]string{"HTTPS_PROXY": "http://proxy.example.com:8080", "NO_PROXY": "localhost,.internal"},
+			assertion: func(t *testing.T, config util.Config) {
+				require.Empty(t, config.Problems)
+				require.Equal(t, "http://proxy.example.com:8080", config.Proxy)
+				require.Equal(t, "localhost,.internal", config.NoProxy)
+				require.False(t, config.InsecureSkipVerify)
+			},
+		},
+		{
+			name: "OK with the config file over HTTPS_PROXY",
+			env:  map[string]string{"HTTPS_PROXY": "http://proxy.example.com:8080"},
+			file: "proxy: http://file.example.com:3128\n",
+			assertion: func(t *testing.T, config util.Config) {
+				require.Equal(t, "http://file.example.com:3128", config.Proxy)
+			},
+		},
+		{
+			name: "OK with GGS_PROXY over the config file",
+			env:  map[string]string{"GGS_PROXY": "proxy.example.com:8080"},
+			file: "proxy: http://file.example.com:3128\n",
+			assertion: func(t *testing.T, config util.Config) {
+				require.Empty(t, config.Problems)
+				require.Equal(t, "proxy.example.com:8080", config.Proxy)
+			},
+		},
+		{
+			name: "OK with GGS_INSECURE_SKIP_VERIFY",
+			env:  map[string]string{"GGS_INSECURE_SKIP_VERIFY": "true"},
+			assertion: func(t *testing.T, config util.Config) {
+				require.Empty(t, config.Problems)
+				require.True(t, config.InsecureSkipVerify)
+			},
+		},
+		{
+			name: "Problem with an invalid proxy",
+			env:  map[string]string{"GGS_PROXY": "ftp://proxy.example.com"},
+			assertion: func(t *testing.T, config util.Config) {
+				require.Len(t, config.Problems, 1)
+				require.Equal(t, "scheme of the proxy must be http, https or socks5, but got 'ftp'", config.Problems[0].Message)
+			},
+		},
+		{
+			name: "Problem with invalid GGS_INSECURE_SKIP_VERIFY",
+			env:  map[string]string{"GGS_INSECURE_SKIP_VERIFY": "yes please"},
+			assertion: func(t *testing.T, config util.Config) {
+				require.Len(t, config.Problems, 1)
+				require.False(t, config.InsecureSkipVerify)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			path := filepath.Join(t.TempDir(), "config.yml")
+			require.NoError(t, ioutil.WriteFile(path, []byte(tc.file), 0600))
+			env := map[string]string{"GGS_CONFIG": path, "GGS_TOKEN": "", "GGS_TOKEN_SOURCE": "env"}
+			for _, key := range []string{"GGS_PROXY", "HTTPS_PROXY", "https_proxy", "HTTP_PROXY", "http_proxy", "NO_PROXY", "no_proxy", "GGS_INSECURE_SKIP_VERIFY"} {
+				env[key] = ""
+			}
+			for key, value := range tc.env {
+				env[key] = value
+			}
+			setenv(t, env)
+
+			// Act
+			config, err := util.LoadConfig()
+
+			// Assert
+			require.NoError(t, err)
+			tc.assertion(t, config)
+		})
+	}
+}
+
+func TestHTTPClientInsecureSkipVerify(t *testing.T) {
+
+	// Arrange
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	defer ts.Close()
+
+	// Act
+	resp, err := util.Config{InsecureSkipVerify: true}.HTTPClient().Get(ts.URL)
+
+	// Assert
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+}
@@ -0,0 +1,81 @@
+package util
+
+import (
+	"io"
+	"regexp"
+)
+
+// Mask of secrets.
+const redacted = "***"
+
+// Patterns of secrets and their replacements.
+// Prefixes of GitHub tokens are kept to tell their types.
+var secretPatterns = []struct {
+	regex       *regexp.Regexp
+	replacement string
+}{
+	// ghp_, gho_, ghu_, ghs_ and ghr_ tokens
+	{regexp.MustCompile(`\b(gh[pousr])_[A-Za-z0-9]{16,}`), "${1}_" + redacted},
+	// Fine-grained personal access tokens
+	{regexp.MustCompile(`\bgithub_pat_[A-Za-z0-9_]{16,}`), "github_pat_" + redacted},
+	// Legacy tokens of 40 hex characters, which can not be told from commit hashes without the scheme.
+	{regexp.MustCompile(`(?i)\b(token|bearer)(\s+)[0-9a-f]{40}\b`), "${1}${2}" + redacted},
+	// JWT of GitHub Apps
+	{regexp.MustCompile(`\beyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+`), "eyJ" + redacted},
+	// Credentials of Authorization headers in dumps of requests
+	{regexp.MustCompile(`(?i)\b((?:proxy-)?authorization:\s*)(\w+\s+)?\S+`), "${1}${2}" + redacted},
+	// Passwords in URLs
+	{regexp.MustCompile(`(://[^/\s:@]+:)[^/\s@]+@`), "${1}" + redacted + "@"},
+	// Tokens in queries and forms
+	{regexp.MustCompile(`(?i)\b(access_token|refresh_token|client_secret|private_token|device_code)=[^&\s"]+`), "${1}=" + redacted},
+}
+
+// Returns the string whose secrets (like GitHub tokens) are masked.
+func Redact(s string) string {
+	for _, p := range secretPatterns {
+		s = p.regex.ReplaceAllString(s, p.replacement)
+	}
+	return s
+}
+
+// Error whose message is redacted.
+type redactedError struct {
+	err error
+}
+
+func (e *redactedError) Error() string {
+	return Redact(e.err.Error())
+}
+
+func (e *redactedError) Unwrap() error {
+	return e.err
+}
+
+// Returns the error whose message is redacted, which still wraps the original error.
+// nil is returned as it is.
+func RedactError(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &redactedError{err: err}
+}
+
+// Writer which redacts what is written.
+// Each write should have whole lines, since secrets split across writes are not detected.
+type redactWriter struct {
+	w io.Writer
+}
+
+// Returns the writer which redacts what is written to w.
+func NewRedactWriter(w io.Writer) io.Writer {
+	return &redactWriter{w: w}
+}
+
+func (r *redactWriter) Write(p []byte) (int, error) {
+
+	if _, err := io.WriteString(r.w, Redact(string(p))); err != nil {
+		return 0, err
+	}
+	// The length of the original is returned, since the redacted one differs.
+	return len(p), nil
+}
@@ -0,0 +1,238 @@
+package util
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Keys of the settings which 'ggs config' gets and sets.
+// Nested keys are separated by dots, and hosts are edited in the file directly.
+var SettingKeys = []string{
+	"profile",
+	"host",
+	"ca_cert",
+	"proxy",
+	"token_source",
+	"user",
+	"include",
+	"exclude",
+	"output",
+	"concurrency",
+	"cache.dir",
+	"cache.ttl",
+	"telemetry.exporter",
+	"telemetry.endpoint",
+}
+
+// Returns the value of the key in the profile (or at the top level if the profile is empty).
+// Lists are joined with commas, and empty means not set.
+func (f FileConfig) Get(profile, key string) (string, error) {
+
+	if key == "profile" {
+		return f.Profile, nil
+	}
+
+	s, err := f.Select(profile)
+	if err != nil {
+		return "", err
+	}
+
+	switch key {
+	case "host":
+		return s.Host, nil
+	case "ca_cert":
+		return s.CACert, nil
+	case "proxy":
+		return s.Proxy, nil
+	case "token_source":
+		return s.TokenSource, nil
+	case "user":
+		return s.User, nil
+	case "include":
+		return strings.Join(s.Include, ","), nil
+	case "exclude":
+		return strings.Join(s.Exclude, ","), nil
+	case "output":
+		return s.Output, nil
+	case "concurrency":
+		if s.Concurrency == 0 {
+			return "", nil
+		}
+		return strconv.Itoa(s.Concurrency), nil
+	case "cache.dir":
+		return s.Cache.Dir, nil
+	case "cache.ttl":
+		if s.Cache.TTL == 0 {
+			return "", nil
+		}
+		return s.Cache.TTL.String(), nil
+	case "telemetry.exporter":
+		return s.Telemetry.Exporter, nil
+	case "telemetry.endpoint":
+		return s.Telemetry.Endpoint, nil
+	default:
+		return "", unknownKeyError(key)
+	}
+}
+
+// Set the value of the key in the config file.
+// The key is set in the profile if given (except the default profile itself), otherwise at the top level.
+// An empty value removes the key, and lists are separated by commas.
+// Comments and other settings in the file are kept.
+func SetFileSetting(path, profile, key, value string) error {
+
+	if err := validateSetting(key, value); err != nil {
+		return err
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to ioutil.ReadFile: %w", err)
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("failed to yaml.Unmarshal %s: %w", path, err)
+	}
+	if doc.Kind == 0 {
+		// Empty file
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode}}}
+	}
+	if doc.Content[0].Kind != yaml.MappingNode {
+		return fmt.Errorf("the config file is not a mapping: %s", path)
+	}
+
+	keys := strings.Split(key, ".")
+	if profile != "" && key != "profile" {
+		keys = append([]string{"profiles", profile}, keys...)
+	}
+
+	node := doc.Content[0]
+	parents := []*yaml.Node{node}
+	for _, k := range keys[:len(keys)-1] {
+		node = mappingValue(node, k)
+		if node.Kind != yaml.MappingNode {
+			// e.g. 'cache:' without settings
+			*node = yaml.Node{Kind: yaml.MappingNode}
+		}
+		parents = append(parents, node)
+	}
+	last := keys[len(keys)-1]
+
+	if value == "" {
+		removeKey(node, last)
+		// Remove mappings which become empty (e.g. 'cache: {}').
+		for i := len(parents) - 1; i > 0 && len(parents[i].Content) == 0; i-- {
+			removeKey(parents[i-1], keys[i-1])
+		}
+	} else if key == "include" || key == "exclude" {
+		list := &yaml.Node{Kind: yaml.SequenceNode}
+		for _, v := range strings.Split(value, ",") {
+			list.Content = append(list.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: strings.TrimSpace(v)})
+		}
+		*mappingValue(node, last) = *list
+	} else {
+		*mappingValue(node, last) = yaml.Node{Kind: yaml.ScalarNode, Value: value}
+	}
+
+	var out strings.Builder
+	encoder := yaml.NewEncoder(&out)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&doc); err != nil {
+		return fmt.Errorf("failed to yaml.Encode: %w", err)
+	}
+
+	// The file may have tokens of hosts.
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to os.MkdirAll: %w", err)
+	}
+	if err := ioutil.WriteFile(path, []byte(out.String()), 0600); err != nil {
+		return fmt.Errorf("failed to ioutil.WriteFile: %w", err)
+	}
+
+	return nil
+}
+
+// Returns an error if the value is invalid for the key.
+func validateSetting(key, value string) error {
+
+	var c Config
+	switch key {
+	case "profile", "host", "ca_cert", "user", "cache.dir":
+		return nil
+	case "proxy":
+		c.Proxy = value
+	case "token_source":
+		c.TokenSourceName = value
+	case "include":
+		c.Filter.Include = strings.Split(value, ",")
+	case "exclude":
+		c.Filter.Exclude = strings.Split(value, ",")
+	case "output":
+		c.Output = value
+	case "concurrency":
+		if value == "" {
+			return nil
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("concurrency must be a number, but got '%s'", value)
+		}
+		c.Concurrency = n
+	case "cache.ttl":
+		if value == "" {
+			return nil
+		}
+		ttl, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("ttl of the cache must be a duration like 1h, but got '%s'", value)
+		}
+		c.Cache.TTL = ttl
+	case "telemetry.exporter":
+		c.Telemetry.Exporter = value
+	case "telemetry.endpoint":
+		c.Telemetry.Endpoint = value
+	default:
+		return unknownKeyError(key)
+	}
+
+	return c.Validate()
+}
+
+// Returns the value of the key in the mapping, which is added if missing.
+func mappingValue(mapping *yaml.Node, key string) *yaml.Node {
+
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i+1]
+		}
+	}
+
+	value := &yaml.Node{Kind: yaml.MappingNode}
+	mapping.Content = append(mapping.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, value)
+	return value
+}
+
+// Remove the key from the mapping.
+func removeKey(mapping *yaml.Node, key string) {
+
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			mapping.Content = append(mapping.Content[:i], mapping.Content[i+2:]...)
+			return
+		}
+	}
+}
+
+// Error of the key which is not one of SettingKeys.
+func unknownKeyError(key string) error {
+	return fmt.Errorf("unknown key '%s': keys are %s", key, strings.Join(SettingKeys, ", "))
+}
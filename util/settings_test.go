@@ -0,0 +1,106 @@
+package util_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kokoichi206/go-git-stats/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetFileSetting(t *testing.T) {
+
+	// Arrange
+	path := filepath.Join(t.TempDir(), "ggs", "config.yml")
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0700))
+	require.NoError(t, ioutil.WriteFile(path, []byte(`# GitHub Enterprise Server
+host: ghe.example.com
+`), 0644))
+
+	// Act
+	require.NoError(t, util.SetFileSetting(path, "", "output", "json"))
+	require.NoError(t, util.SetFileSetting(path, "work", "include", "my-org/*, kokoichi206/*"))
+	require.NoError(t, util.SetFileSetting(path, "work", "cache.ttl", "1h"))
+	require.NoError(t, util.SetFileSetting(path, "work", "profile", "work"))
+
+	// Assert
+	data, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, `# GitHub Enterprise Server
+host: ghe.example.com
+output: json
+profiles:
+  work:
+    include:
+      - my-org/*
+      - kokoichi206/*
+    cache:
+      ttl: 1h
+profile: work
+`, string(data))
+
+	// The permission of the existing file is kept.
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0644), info.Mode().Perm())
+
+	file, err := util.LoadFileConfig(path)
+	require.NoError(t, err)
+	for key, expected := range map[string]string{
+		"profile":   "work",
+		"host":      "ghe.example.com",
+		"output":    "json",
+		"include":   "my-org/*,kokoichi206/*",
+		"cache.ttl": "1h0m0s",
+		"user":      "",
+	} {
+		value, err := file.Get("work", key)
+		require.NoError(t, err)
+		require.Equal(t, expected, value, key)
+	}
+
+	// Removing the key removes the empty mapping as well.
+	require.NoError(t, util.SetFileSetting(path, "work", "cache.ttl", ""))
+	data, err = ioutil.ReadFile(path)
+	require.NoError(t, err)
+	require.NotContains(t, string(data), "cache")
+}
+
+func TestSetFileSettingError(t *testing.T) {
+
+	path := filepath.Join(t.TempDir(), "config.yml")
+
+	testCases := []struct {
+		key   string
+		value string
+		err   string
+	}{
+		{"hosts", "github.com", "unknown key 'hosts': keys are profile, host, ca_cert, proxy, token_source, user, include, exclude, output, concurrency, cache.dir, cache.ttl, telemetry.exporter, telemetry.endpoint"},
+		{"output", "xml", "output must be text or json, but got 'xml'"},
+		{"concurrency", "many", "concurrency must be a number, but got 'many'"},
+		{"concurrency", "-1", "concurrency must not be negative, but got -1"},
+		{"cache.ttl", "1 hour", "ttl of the cache must be a duration like 1h, but got '1 hour'"},
+		{"token_source", "keyring", "token source must be one of credentials, env, gh, git-credential, but got 'keyring'"},
+		{"exclude", "[a", "invalid pattern of repositories: '[a'"},
+		{"telemetry.exporter", "jaeger", "exporter of the telemetry must be stdout, stderr or otlp, but got 'jaeger'"},
+		{"telemetry.endpoint", "localhost:4318", "endpoint of the telemetry must be a URL like http://localhost:4318, but got 'localhost:4318'"},
+	}
+
+	for _, tc := range testCases {
+		err := util.SetFileSetting(path, "", tc.key, tc.value)
+		require.Error(t, err)
+		require.Equal(t, tc.err, err.Error())
+	}
+
+	// Nothing is written.
+	_, err := os.Stat(path)
+	require.True(t, os.IsNotExist(err))
+
+	// Only the user can read the new file, which may have tokens later.
+	require.NoError(t, util.SetFileSetting(path, "", "user", "kokoichi206"))
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0600), info.Mode().Perm())
+}
@@ -0,0 +1,60 @@
+package util
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+)
+
+// Exporters of the telemetry.
+const (
+	TelemetryStdout = "stdout"
+	TelemetryStderr = "stderr"
+	TelemetryOTLP   = "otlp"
+)
+
+// Endpoint of OTLP/HTTP of a local OpenTelemetry collector.
+const DefaultOTLPEndpoint = "http://localhost:4318"
+
+// Settings of the telemetry (spans and metrics) of commands and API calls.
+type TelemetryConfig struct {
+	// Exporter of spans and metrics (stdout, stderr or otlp), empty disables the telemetry.
+	Exporter string `yaml:"exporter"`
+	// Base URL of OTLP/HTTP, empty means OTEL_EXPORTER_OTLP_ENDPOINT or DefaultOTLPEndpoint.
+	Endpoint string `yaml:"endpoint"`
+}
+
+// Returns whether spans and metrics are recorded.
+func (c TelemetryConfig) Enabled() bool {
+	return c.Exporter != ""
+}
+
+// Returns the base URL of OTLP/HTTP.
+func (c TelemetryConfig) OTLPEndpoint() string {
+
+	if c.Endpoint != "" {
+		return c.Endpoint
+	}
+	if endpoint := os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT"); endpoint != "" {
+		return endpoint
+	}
+	return DefaultOTLPEndpoint
+}
+
+func (c TelemetryConfig) validate() error {
+
+	switch c.Exporter {
+	case "", TelemetryStdout, TelemetryStderr, TelemetryOTLP:
+	default:
+		return fmt.Errorf("exporter of the telemetry must be %s, %s or %s, but got '%s'", TelemetryStdout, TelemetryStderr, TelemetryOTLP, c.Exporter)
+	}
+
+	if c.Endpoint != "" {
+		u, err := url.Parse(c.Endpoint)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("endpoint of the telemetry must be a URL like %s, but got '%s'", DefaultOTLPEndpoint, c.Endpoint)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,248 @@
+package util
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Sources of the GitHub access token.
+const (
+	TokenSourceEnv           = "GGS_TOKEN"
+	TokenSourceCredentials   = "credentials file"
+	TokenSourceConfigFile    = "config file"
+	TokenSourceGh            = "gh hosts.yml"
+	TokenSourceGitCredential = "git credential"
+	TokenSourceApp           = "GitHub App"
+)
+
+// Time limit of 'git credential fill'.
+const gitCredentialTimeout = 5 * time.Second
+
+// A source of the token of the host, which returns the name of the source and the token (empty if not found).
+type tokenSource func(host string) (source string, token string, err error)
+
+// Sources in the order of precedence.
+var tokenChain = []tokenSource{
+	envToken,
+	storedToken,
+	ghEnvToken,
+	ghHostsToken,
+	gitCredentialToken,
+}
+
+// Names of the sources in the config file (token_source).
+// GGS_TOKEN is always looked up first since environment variables take precedence over the config file.
+var tokenSourceNames = map[string][]tokenSource{
+	// Only GGS_TOKEN (public data without it).
+	"env":            {},
+	"credentials":    {storedToken},
+	"gh":             {ghEnvToken, ghHostsToken},
+	"git-credential": {gitCredentialToken},
+}
+
+// Returns the names of the sources in the config file.
+func TokenSourceNames() []string {
+
+	names := make([]string, 0, len(tokenSourceNames))
+	for name := range tokenSourceNames {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// Returns the sources of the token except GGS_TOKEN.
+// An empty name means all sources.
+func (c Config) tokenSources() []tokenSource {
+
+	if sources, ok := tokenSourceNames[c.TokenSourceName]; ok {
+		return sources
+	}
+	return tokenChain[1:]
+}
+
+// Resolve the GitHub access token of the host in the order of
+// [GGS_TOKEN], the credential stored by 'ggs auth login', [GH_TOKEN] or [GITHUB_TOKEN]
+// ([GH_ENTERPRISE_TOKEN] or [GITHUB_ENTERPRISE_TOKEN] for GitHub Enterprise Server),
+// hosts.yml of gh and 'git credential fill'.
+// Sources other than GGS_TOKEN are limited to the one of the token_source setting if set.
+// The GitHub App takes precedence over them if configured.
+func (c *Config) ResolveToken() error {
+
+	token, source, err := lookupToken(c.Host, append([]tokenSource{envToken}, c.tokenSources()...))
+	var problemErr interface{ problem() Problem }
+	if errors.As(err, &problemErr) {
+		// The token is not used, and commands stop with the problem.
+		c.Problems = append(c.Problems, problemErr.problem())
+		token, source, err = "", "", nil
+	}
+	if err != nil {
+		return err
+	}
+
+	c.Token = token
+	c.TokenSource = source
+	if c.App.Configured() {
+		// The installation token of the app is used instead.
+		c.TokenSource = TokenSourceApp
+	}
+	return nil
+}
+
+// Returns the token of the host from the first source which has it.
+func lookupToken(host string, chain []tokenSource) (string, string, error) {
+
+	for _, lookup := range chain {
+		source, token, err := lookup(host)
+		if err != nil {
+			return "", "", err
+		}
+		if token != "" {
+			return token, source, nil
+		}
+	}
+
+	return "", "", nil
+}
+
+// Token of [GGS_TOKEN].
+func envToken(host string) (string, string, error) {
+
+	token := os.Getenv("GGS_TOKEN")
+	if !isValidFormat(token) {
+		return "", "", &TokenFormatError{Source: "GGS_TOKEN"}
+	}
+
+	return TokenSourceEnv, token, nil
+}
+
+// Token stored by 'ggs auth login'.
+// A locked file without the secret has no token, so that the next sources are tried.
+func storedToken(host string) (string, string, error) {
+
+	credential, _, err := loadCredential(host)
+	var lockedErr *CredentialsLockedError
+	if errors.As(err, &lockedErr) && lockedErr.Err == nil {
+		return "", "", nil
+	}
+	return TokenSourceCredentials, credential.Token, err
+}
+
+// Token of the environment variables of gh.
+// see: https://cli.github.com/manual/gh_help_environment
+func ghEnvToken(host string) (string, string, error) {
+
+	keys := []string{"GH_TOKEN", "GITHUB_TOKEN"}
+	if isEnterpriseServer(host) {
+		keys = []string{"GH_ENTERPRISE_TOKEN", "GITHUB_ENTERPRISE_TOKEN"}
+	}
+
+	for _, key := range keys {
+		token := os.Getenv(key)
+		if token == "" {
+			continue
+		}
+		if !isValidFormat(token) {
+			return "", "", &TokenFormatError{Source: key}
+		}
+		return key, token, nil
+	}
+
+	return "", "", nil
+}
+
+// Token of the host in hosts.yml of gh.
+// Tokens in the system keyring are not read.
+func ghHostsToken(host string) (string, string, error) {
+
+	dir := os.Getenv("GH_CONFIG_DIR")
+	if dir == "" {
+		if xdg := os.Getenv("XDG_CONFIG_HOME"); xdg != "" {
+			dir = filepath.Join(xdg, "gh")
+		} else if home, err := os.UserHomeDir(); err == nil {
+			dir = filepath.Join(home, ".config", "gh")
+		} else {
+			return "", "", nil
+		}
+	}
+	path := filepath.Join(dir, "hosts.yml")
+
+	data, err := ioutil.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", "", nil
+	}
+	if err != nil {
+		return "", "", fmt.Errorf("failed to ioutil.ReadFile: %w", err)
+	}
+
+	var hosts map[string]struct {
+		OAuthToken string `yaml:"oauth_token"`
+	}
+	if err := yaml.Unmarshal(data, &hosts); err != nil {
+		return "", "", fmt.Errorf("failed to yaml.Unmarshal %s: %w", path, err)
+	}
+
+	token := hosts[HostName(host)].OAuthToken
+	if !isValidFormat(token) {
+		return "", "", nil
+	}
+
+	return TokenSourceGh, token, nil
+}
+
+// Password of the host from the git credential helpers.
+// Users are never prompted, and passwords which are not GitHub tokens are ignored.
+// see: https://git-scm.com/docs/git-credential
+func gitCredentialToken(host string) (string, string, error) {
+
+	u, err := parseHost(host)
+	if err != nil {
+		return "", "", err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), gitCredentialTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "git", "credential", "fill")
+	cmd.Stdin = strings.NewReader(fmt.Sprintf("protocol=%s\nhost=%s\n\n", u.Scheme, u.Host))
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0", "GIT_ASKPASS=", "SSH_ASKPASS=", "GCM_INTERACTIVE=never")
+	var stdout bytes.Buffer
+	cmd.Stdout = &stdout
+
+	// git is not installed, or no helpers have the credential.
+	if err := cmd.Run(); err != nil {
+		return "", "", nil
+	}
+
+	scanner := bufio.NewScanner(&stdout)
+	for scanner.Scan() {
+		if password := strings.TrimPrefix(scanner.Text(), "password="); password != scanner.Text() {
+			if password == "" || !isValidFormat(password) {
+				return "", "", nil
+			}
+			return TokenSourceGitCredential, password, nil
+		}
+	}
+
+	return "", "", nil
+}
+
+// Returns whether the host is GitHub Enterprise Server (neither GitHub.com nor *.ghe.com).
+func isEnterpriseServer(host string) bool {
+
+	apiBaseURL, _, err := GitHubEndpoints(host)
+	return err == nil && strings.HasSuffix(apiBaseURL, "/api/v3")
+}